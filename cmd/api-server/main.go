@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -10,50 +12,151 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/config"
+	"github.com/prashsamosa/newsapi/internal/health"
+	"github.com/prashsamosa/newsapi/internal/idempotency"
 	"github.com/prashsamosa/newsapi/internal/logger"
+	"github.com/prashsamosa/newsapi/internal/metrics"
+	"github.com/prashsamosa/newsapi/internal/migration"
 	"github.com/prashsamosa/newsapi/internal/news"
 	"github.com/prashsamosa/newsapi/internal/postgres"
+	"github.com/prashsamosa/newsapi/internal/requestid"
+	"github.com/prashsamosa/newsapi/internal/retention"
 	"github.com/prashsamosa/newsapi/internal/router"
+	"github.com/prashsamosa/newsapi/internal/scheduler"
+	"github.com/prashsamosa/newsapi/internal/tlsconfig"
+	"github.com/prashsamosa/newsapi/internal/tracing"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"golang.org/x/sync/errgroup"
 )
 
 func main() {
 	log := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))
 
+	cfg, args, err := config.Parse(os.Args[0], os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err == nil && len(args) > 0 {
+		err = fmt.Errorf("unexpected arguments: %v", args)
+	}
+	if err != nil {
+		log.Error("config error", "err", err)
+		os.Exit(1)
+	}
+	log.Info("config loaded", "config", cfg)
+
 	db, err := postgres.NewDB(&postgres.Config{
-		Host:     os.Getenv("DATABASE_HOST"),
-		DBName:   os.Getenv("DATABASE_NAME"),
-		Password: os.Getenv("DATABASE_PASSWORD"),
-		User:     os.Getenv("DATABASE_USER"),
-		Port:     os.Getenv("DATABASE_PORT"),
-		SSLMode:  "disable",
+		Host:        cfg.Database.Host,
+		DBName:      cfg.Database.Name,
+		Password:    string(cfg.Database.Password),
+		User:        cfg.Database.User,
+		Port:        cfg.Database.Port,
+		SSLMode:     cfg.Database.SSLMode,
+		MaxOpenConn: cfg.Database.MaxOpenConns,
+		MaxIdleConn: cfg.Database.MaxIdleConns,
+		Debug:       cfg.Database.Debug,
 	})
 	if err != nil {
 		log.Error("db error", "err", err)
 		os.Exit(1)
 	}
+	m := metrics.New()
+	m.InstrumentDB(db)
 	newsStore := news.NewStore(db)
+	idempotencyStore := idempotency.NewStore(db, cfg.Idempotency.KeyTTL, cfg.Idempotency.Lease)
+
+	authenticator, err := newAuthenticator(db, cfg.JWT)
+	if err != nil {
+		log.Error("config error", "err", err)
+		os.Exit(1)
+	}
+
+	probes := health.New(health.DefaultTimeout,
+		health.DBCheck(db),
+		health.MigrationsCheck(migrate.NewMigrator(db, migration.New())),
+	)
 
-	r := router.New(newsStore)
-	wrappedRouter := logger.AddLoggerMid(log, logger.Middleware(r))
+	routerOpts := router.Options{
+		Idempotency:   idempotencyStore,
+		Authenticator: authenticator,
+		Metrics:       m,
+		Health:        probes,
+	}
+	tp, err := newTracerProvider(context.Background(), cfg.Tracing.Exporter)
+	if err != nil {
+		log.Error("config error", "err", err)
+		os.Exit(1)
+	}
+	if tp != nil {
+		tracing.InstrumentDB(tp, db)
+		routerOpts.TracerProvider = tp
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := tp.Shutdown(ctx); err != nil {
+				log.Error("failed to flush the traces", "err", err)
+			}
+		}()
+	}
 
-	log.Info("server starting on port 8080")
+	r := router.New(newsStore, routerOpts)
+	wrappedRouter := logger.AddLoggerMid(log, requestid.Middleware(logger.Middleware(r)))
 
 	server := &http.Server{
-		Addr:              ":8080",
-		ReadHeaderTimeout: 3 * time.Second,
+		Addr:              cfg.Server.Addr,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
 		Handler:           wrappedRouter,
 	}
+	var certs *tlsconfig.Reloader
+	if cfg.TLS.CertFile != "" {
+		if certs, err = newCertReloader(cfg.TLS); err != nil {
+			log.Error("config error", "err", err)
+			os.Exit(1)
+		}
+		server.TLSConfig = certs.TLSConfig()
+	}
+
+	log.Info("server starting", "addr", cfg.Server.Addr, "tls", certs != nil)
 
 	errGrp, errGrpCtx := errgroup.WithContext(context.Background())
 	errGrp.Go(func() error {
-		if err := server.ListenAndServe(); err != nil {
+		listen := server.ListenAndServe
+		if certs != nil {
+			// The certificates are served by the TLS config, HTTP/2 is
+			// negotiated with ALPN.
+			listen = func() error { return server.ListenAndServeTLS("", "") }
+		}
+		if err := listen(); err != nil {
 			log.Error("failed to start server", "error", err)
 			return fmt.Errorf("error starting server: %w", err)
 		}
 		return nil
 	})
 
+	// Background jobs are stopped with the server.
+	jobsCtx, stopJobs := context.WithCancel(logger.CtxWithLogger(errGrpCtx, log))
+	defer stopJobs()
+	errGrp.Go(func() error {
+		return retention.NewJob("idempotency_keys", idempotencyStore, idempotencyStore.TTL(), time.Hour).Run(jobsCtx)
+	})
+	errGrp.Go(func() error {
+		return scheduler.New(newsStore, cfg.Scheduler.Interval, time.Now).Run(jobsCtx)
+	})
+	if certs != nil {
+		errGrp.Go(func() error {
+			return certs.Run(jobsCtx, cfg.TLS.ReloadInterval)
+		})
+	}
+	if cfg.Trash.Retention > 0 {
+		errGrp.Go(func() error {
+			return retention.NewJob("trash", retention.PurgerFunc(newsStore.PurgeTrashedBefore), cfg.Trash.Retention, cfg.Trash.PurgeInterval).Run(jobsCtx)
+		})
+	}
+
 	errGrp.Go(func() error {
 		sigch := make(chan os.Signal, 1)
 		signal.Notify(sigch, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
@@ -62,8 +165,17 @@ func main() {
 			log.Info("signal received", "signal", sig)
 		case <-errGrpCtx.Done():
 		}
+		// Fail the readiness probe first, so that the server is removed from
+		// the load balancers before it stops accepting connections.
+		probes.Shutdown()
+		log.Info("draining traffic", "delay", cfg.Server.ShutdownDelay)
+		select {
+		case <-time.After(cfg.Server.ShutdownDelay):
+		case <-errGrpCtx.Done():
+		}
+		stopJobs()
 
-		ctxWithTimeout, cancelFn := context.WithTimeout(errGrpCtx, 5*time.Second)
+		ctxWithTimeout, cancelFn := context.WithTimeout(errGrpCtx, cfg.Server.ShutdownTimeout)
 		defer cancelFn()
 
 		log.Info("initiating graceful shutdown")
@@ -79,3 +191,71 @@ func main() {
 		log.Error("error running", "err", err)
 	}
 }
+
+// newAuthenticator returns the authenticator of the API keys, and of the
+// JWTs when the JSON Web Key Set is configured.
+func newAuthenticator(db bun.IDB, cfg config.JWT) (auth.Authenticator, error) {
+	chain := auth.Chain{auth.NewKeyStore(db)}
+	if cfg.JWKS == "" {
+		return chain, nil
+	}
+
+	keys, err := auth.NewKeySet(cfg.JWKS, cfg.JWKSRefresh)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	jwtCfg := auth.JWTConfig{
+		Issuer:     cfg.Issuer,
+		Audience:   cfg.Audience,
+		RolesClaim: cfg.RolesClaim,
+		Leeway:     cfg.Leeway,
+	}
+	if cfg.RoleScopes != "" {
+		if jwtCfg.RoleScopes, err = auth.ParseRoleScopes(cfg.RoleScopes); err != nil {
+			return nil, fmt.Errorf("invalid jwt.role_scopes: %w", err)
+		}
+	}
+	jwtAuth, err := auth.NewJWTAuthenticator(keys, jwtCfg)
+	if err != nil {
+		return nil, err
+	}
+	return append(chain, jwtAuth), nil
+}
+
+// newCertReloader returns the reloader of the certificates of the HTTPS
+// server.
+func newCertReloader(cfg config.TLS) (*tlsconfig.Reloader, error) {
+	clientAuth, err := tlsconfig.ParseClientAuth(cfg.ClientAuth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls.client_auth: %w", err)
+	}
+	minVersion, err := tlsconfig.ParseVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls.min_version: %w", err)
+	}
+	cipherSuites, err := tlsconfig.ParseCipherSuites(cfg.CipherSuites)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls.cipher_suites: %w", err)
+	}
+	return tlsconfig.New(tlsconfig.Config{
+		CertFile:     cfg.CertFile,
+		KeyFile:      cfg.KeyFile,
+		ClientCAFile: cfg.ClientCAFile,
+		ClientAuth:   clientAuth,
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+	})
+}
+
+// newTracerProvider returns the tracer provider exporting the spans with
+// the exporter, nil when tracing is disabled.
+func newTracerProvider(ctx context.Context, exporter string) (*sdktrace.TracerProvider, error) {
+	exp, err := tracing.NewExporter(ctx, exporter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing.exporter: %w", err)
+	}
+	if exp == nil {
+		return nil, nil //nolint:nilnil // tracing is disabled.
+	}
+	return tracing.NewProvider(exp), nil
+}
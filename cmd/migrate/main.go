@@ -1,27 +1,46 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/config"
 	"github.com/prashsamosa/newsapi/internal/migration"
 	"github.com/prashsamosa/newsapi/internal/postgres"
+	"github.com/google/uuid"
 	"github.com/uptrace/bun/extra/bundebug"
 	"github.com/uptrace/bun/migrate"
 	"github.com/urfave/cli/v2"
 )
 
 func main() {
+	// The configuration flags come before the command, e.g.
+	// migrate -config config.yaml migrate up.
+	cfg, args, err := config.Parse(os.Args[0], os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	db, err := postgres.NewDB(&postgres.Config{
-		Host:     os.Getenv("DATABASE_HOST"),
-		DBName:   os.Getenv("DATABASE_NAME"),
-		Password: os.Getenv("DATABASE_PASSWORD"),
-		User:     os.Getenv("DATABASE_USER"),
-		Port:     os.Getenv("DATABASE_PORT"),
-		SSLMode:  "disable",
+		Host:        cfg.Database.Host,
+		DBName:      cfg.Database.Name,
+		Password:    string(cfg.Database.Password),
+		User:        cfg.Database.User,
+		Port:        cfg.Database.Port,
+		SSLMode:     cfg.Database.SSLMode,
+		MaxOpenConn: cfg.Database.MaxOpenConns,
+		MaxIdleConn: cfg.Database.MaxIdleConns,
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -40,9 +59,10 @@ func main() {
 				migrate.NewMigrator(db, migration.New(), migrate.WithMarkAppliedOnSuccess(true)),
 				l,
 			),
+			newAPIKeyCmd(auth.NewKeyStore(db)),
 		},
 	}
-	if err := app.Run(os.Args); err != nil {
+	if err := app.Run(append([]string{os.Args[0]}, args...)); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -136,3 +156,77 @@ func newMigrationCmd(m *migrate.Migrator, l *slog.Logger) *cli.Command {
 		},
 	}
 }
+
+func newAPIKeyCmd(ks *auth.KeyStore) *cli.Command {
+	return &cli.Command{
+		Name:  "apikey",
+		Usage: "manage api keys",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "create",
+				Usage: "create an api key, printed only once",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "name", Usage: "name of the key owner", Required: true},
+					&cli.StringSliceFlag{Name: "scope", Usage: "scope granted to the key, repeatable", Required: true},
+					&cli.DurationFlag{Name: "expires-in", Usage: "lifetime of the key, never expires when not set"},
+				},
+				Action: func(ctx *cli.Context) error {
+					scopes, err := auth.ParseScopes(ctx.StringSlice("scope"))
+					if err != nil {
+						return fmt.Errorf("parse scopes: %w", err)
+					}
+					var expiresAt time.Time
+					if d := ctx.Duration("expires-in"); d > 0 {
+						expiresAt = time.Now().Add(d)
+					}
+					key, k, err := ks.Create(ctx.Context, ctx.String("name"), scopes, expiresAt)
+					if err != nil {
+						return fmt.Errorf("create api key: %w", err)
+					}
+					fmt.Fprintf(ctx.App.Writer, "id: %s\nkey: %s\n", k.ID, key)
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "list the api keys",
+				Action: func(ctx *cli.Context) error {
+					keys, err := ks.List(ctx.Context)
+					if err != nil {
+						return fmt.Errorf("list api keys: %w", err)
+					}
+					w := tabwriter.NewWriter(ctx.App.Writer, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tEXPIRES\tREVOKED")
+					for _, k := range keys {
+						fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\t%s\t%s\n",
+							k.ID, k.Name, k.Prefix, k.Scopes, formatTime(k.CreatedAt), formatTime(k.ExpiresAt), formatTime(k.RevokedAt))
+					}
+					return w.Flush()
+				},
+			},
+			{
+				Name:      "revoke",
+				Usage:     "revoke an api key",
+				ArgsUsage: "<id>",
+				Action: func(ctx *cli.Context) error {
+					id, err := uuid.Parse(ctx.Args().First())
+					if err != nil {
+						return fmt.Errorf("parse api key id: %w", err)
+					}
+					if err := ks.Revoke(ctx.Context, id); err != nil {
+						return fmt.Errorf("revoke api key: %w", err)
+					}
+					fmt.Fprintf(ctx.App.Writer, "revoked: %s\n", id)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// APIKeyPrefix starts every API key, to make them easy to recognise.
+const APIKeyPrefix = "nk_"
+
+var (
+	// ErrInvalidCredentials is returned when the credentials of a request
+	// are unknown, revoked or expired.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrKeyNotFound is returned when revoking an unknown key.
+	ErrKeyNotFound = errors.New("api key not found")
+)
+
+// APIKey used to represent an API key in the database. Only the hash of
+// the key is stored.
+type APIKey struct {
+	bun.BaseModel `bun:"table:api_keys"`
+	ID            uuid.UUID `bun:"id,pk,type:uuid,default:uuid_generate_v4()"`
+	Name          string    `bun:"name,notnull"`
+	// Prefix is the start of the key, to recognise it in listings.
+	Prefix    string    `bun:"prefix,notnull"`
+	Hash      string    `bun:"hash,notnull"`
+	Scopes    []Scope   `bun:"scopes,notnull,array"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	ExpiresAt time.Time `bun:"expires_at,nullzero"`
+	RevokedAt time.Time `bun:"revoked_at,nullzero"`
+}
+
+// KeyStore is a wrapper around bun.DB.
+type KeyStore struct {
+	db bun.IDB
+}
+
+// NewKeyStore returns an instance of API key store.
+func NewKeyStore(db bun.IDB) *KeyStore {
+	return &KeyStore{
+		db: db,
+	}
+}
+
+// Create generates a new API key with the scopes. The key is only returned
+// here and cannot be recovered later. A zero expiresAt never expires.
+func (s KeyStore) Create(ctx context.Context, name string, scopes []Scope, expiresAt time.Time) (string, *APIKey, error) {
+	if name == "" {
+		return "", nil, errors.New("api key name cannot be empty")
+	}
+	if len(scopes) == 0 {
+		return "", nil, errors.New("api key needs at least one scope")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("generate api key: %w", err)
+	}
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	k := &APIKey{
+		ID:        uuid.New(),
+		Name:      name,
+		Prefix:    key[:len(APIKeyPrefix)+6],
+		Hash:      hashKey(key),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if _, err := s.db.NewInsert().Model(k).Exec(ctx); err != nil {
+		return "", nil, fmt.Errorf("create api key: %w", err)
+	}
+	return key, k, nil
+}
+
+// List returns all the API keys, including the revoked ones.
+func (s KeyStore) List(ctx context.Context) ([]*APIKey, error) {
+	var keys []*APIKey
+	if err := s.db.NewSelect().Model(&keys).Order("created_at ASC").Scan(ctx); err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke disables an API key.
+func (s KeyStore) Revoke(ctx context.Context, id uuid.UUID) error {
+	r, err := s.db.NewUpdate().
+		Model((*APIKey)(nil)).
+		Set("revoked_at = current_timestamp").
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("revoke api key: %w", err)
+	}
+	rowsAffected, err := r.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("revoke api key: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate returns the principal of a valid API key.
+func (s KeyStore) Authenticate(ctx context.Context, key string) (*Principal, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrInvalidCredentials
+	}
+	var k APIKey
+	err := s.db.NewSelect().
+		Model(&k).
+		Where("hash = ?", hashKey(key)).
+		Where("revoked_at IS NULL").
+		Where("expires_at IS NULL OR expires_at > current_timestamp").
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("find api key: %w", err)
+	}
+	return &Principal{Subject: k.ID.String(), Name: k.Name, Scopes: k.Scopes}, nil
+}
+
+// hashKey returns the stored representation of the key. The keys are long
+// random strings, so a fast hash is enough.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
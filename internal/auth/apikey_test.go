@@ -0,0 +1,136 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/postgres/postgrestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+var db *bun.DB
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	pdb, cf, err := postgrestest.NewDB(ctx, "testdata/sql/store.sql")
+	if errors.Is(err, postgrestest.ErrUnavailable) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(m.Run())
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	db = pdb
+	code := m.Run()
+
+	if err := cf(ctx); err != nil {
+		panic(err)
+	}
+
+	os.Exit(code)
+}
+
+func TestKeyStore_Create(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	testCases := []struct {
+		name        string
+		keyName     string
+		scopes      []auth.Scope
+		expectedErr bool
+	}{
+		{name: "missing name", scopes: []auth.Scope{auth.ScopeNewsRead}, expectedErr: true},
+		{name: "missing scopes", keyName: "reader", expectedErr: true},
+		{name: "success", keyName: "reader", scopes: []auth.Scope{auth.ScopeNewsRead}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := auth.NewKeyStore(db)
+
+			key, k, err := s.Create(context.Background(), tc.keyName, tc.scopes, time.Time{})
+
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, key, auth.APIKeyPrefix)
+			assert.Contains(t, key, k.Prefix)
+			assert.NotContains(t, k.Hash, key)
+			assert.Equal(t, tc.scopes, k.Scopes)
+		})
+	}
+}
+
+func TestKeyStore_Authenticate(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := auth.NewKeyStore(db)
+	ctx := context.Background()
+	key, k, err := s.Create(ctx, "writer", []auth.Scope{auth.ScopeNewsWrite}, time.Time{})
+	require.NoError(t, err)
+	expiredKey, _, err := s.Create(ctx, "expired", []auth.Scope{auth.ScopeNewsWrite}, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		key         string
+		expectedErr error
+	}{
+		{name: "valid", key: key},
+		{name: "unknown", key: auth.APIKeyPrefix + "unknown", expectedErr: auth.ErrInvalidCredentials},
+		{name: "not an api key", key: "token", expectedErr: auth.ErrInvalidCredentials},
+		{name: "expired", key: expiredKey, expectedErr: auth.ErrInvalidCredentials},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := s.Authenticate(ctx, tc.key)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, k.ID.String(), p.Subject)
+			assert.Equal(t, "writer", p.Name)
+			assert.Equal(t, []auth.Scope{auth.ScopeNewsWrite}, p.Scopes)
+		})
+	}
+}
+
+func TestKeyStore_Revoke(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := auth.NewKeyStore(db)
+	ctx := context.Background()
+	key, k, err := s.Create(ctx, "revoked later", []auth.Scope{auth.ScopeNewsRead}, time.Time{})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Revoke(ctx, k.ID))
+
+	_, err = s.Authenticate(ctx, key)
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	assert.ErrorIs(t, s.Revoke(ctx, k.ID), auth.ErrKeyNotFound)
+	assert.ErrorIs(t, s.Revoke(ctx, uuid.MustParse("0b3c7c3e-5f4a-4d59-9d1f-2a8f3c1d7e01")), auth.ErrKeyNotFound)
+	assert.ErrorIs(t, s.Revoke(ctx, uuid.New()), auth.ErrKeyNotFound)
+}
+
+func TestKeyStore_List(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := auth.NewKeyStore(db)
+
+	keys, err := s.List(context.Background())
+
+	require.NoError(t, err)
+	require.NotEmpty(t, keys)
+	assert.Equal(t, "revoked", keys[0].Name)
+	assert.False(t, keys[0].RevokedAt.IsZero())
+}
@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrUnknownKey is returned when a token is signed with a key missing from
+// the key set.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySet provides the public keys verifying the token signatures.
+type KeySet interface {
+	// Key returns the public key with the key id.
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// jwk is a JSON Web Key (RFC 7517). Only the public RSA and EC signing keys
+// are supported.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS parses a JSON Web Key Set into public keys by key id. Keys not
+// meant for signatures or of an unsupported type are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("unmarshal jwks: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = k.rsaKey()
+		case "EC":
+			key, err = k.ecKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse jwk %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no signing keys")
+	}
+	return keys, nil
+}
+
+func (k jwk) rsaKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("modulus: %w", err)
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("exponent: %w", err)
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k jwk) ecKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+	}
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("x: %w", err)
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("y: %w", err)
+	}
+	key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	if _, err := key.ECDH(); err != nil {
+		return nil, fmt.Errorf("invalid point: %w", err)
+	}
+	return key, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// StaticKeySet is a key set loaded once.
+type StaticKeySet map[string]crypto.PublicKey
+
+// Key implements KeySet.
+func (s StaticKeySet) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := s[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// LoadKeySetFile reads the JSON Web Key Set in the file.
+func LoadKeySetFile(path string) (StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks: %w", err)
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RemoteKeySet fetches the JSON Web Key Set from a URL. The keys are cached
+// and fetched again when they are older than the refresh interval or when a
+// token uses an unknown key id, at most once per minimum interval.
+type RemoteKeySet struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+	// fetches shares a fetch between the concurrent callers.
+	fetches singleflight.Group
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+// minRefreshInterval limits the fetches caused by unknown key ids.
+const minRefreshInterval = time.Minute
+
+// NewRemoteKeySet returns a key set fetched from the URL.
+func NewRemoteKeySet(url string, client *http.Client, refresh time.Duration) *RemoteKeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &RemoteKeySet{url: url, client: client, refresh: refresh}
+}
+
+// Key implements KeySet. Unknown key ids wait for the keys to be fetched
+// again, while stale keys keep being returned when they are.
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	stale := time.Since(s.fetchedAt) >= s.refresh
+	s.mu.RUnlock()
+	if ok && !stale {
+		return key, nil
+	}
+
+	// The fetch outlives the caller starting it, so that a cancelled
+	// request does not fail the other ones.
+	done := s.fetches.DoChan("jwks", func() (any, error) {
+		return nil, s.Refresh(context.WithoutCancel(ctx))
+	})
+	if ok {
+		// Keep using the cached key while the keys are fetched, and when
+		// the issuer is unavailable.
+		return key, nil
+	}
+	select {
+	case res := <-done:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mu.RLock()
+	key, ok = s.keys[kid]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// Refresh fetches the keys, unless they were fetched less than the minimum
+// interval ago.
+func (s *RemoteKeySet) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if time.Since(s.attemptedAt) < minRefreshInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.attemptedAt = time.Now()
+	s.mu.Unlock()
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.keys, s.fetchedAt = keys, time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("jwks request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read jwks: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// NewKeySet returns the key set of a JWKS location, either an http(s) URL
+// or a local file. Remote keys are fetched right away, so that an invalid
+// location fails at startup.
+func NewKeySet(location string, refresh time.Duration) (KeySet, error) {
+	if strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://") {
+		ks := NewRemoteKeySet(location, nil, refresh)
+		if err := ks.Refresh(context.Background()); err != nil {
+			return nil, err
+		}
+		return ks, nil
+	}
+	return LoadKeySetFile(location)
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultRolesClaim is the claim holding the roles of the token subject.
+const DefaultRolesClaim = "roles"
+
+// DefaultRoleScopes maps the token roles to scopes when no mapping is
+// configured.
+var DefaultRoleScopes = map[string][]Scope{
+	"reader": {ScopeNewsRead},
+	"writer": {ScopeNewsWrite},
+	"admin":  {ScopeNewsAdmin},
+}
+
+// signingMethods are the asymmetric algorithms accepted for the tokens.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// JWTConfig holds the validation rules of the bearer tokens.
+type JWTConfig struct {
+	// Issuer must match the iss claim.
+	Issuer string
+	// Audience must be one of the aud claim.
+	Audience string
+	// RolesClaim is the claim holding the roles, DefaultRolesClaim when
+	// empty. Nested claims are separated by dots, e.g. realm_access.roles.
+	RolesClaim string
+	// RoleScopes maps the roles to scopes, DefaultRoleScopes when nil.
+	RoleScopes map[string][]Scope
+	// Leeway allows for clock skew when checking the time claims.
+	Leeway time.Duration
+}
+
+// JWTAuthenticator validates JSON Web Tokens signed by the keys of a JSON
+// Web Key Set.
+type JWTAuthenticator struct {
+	keys   KeySet
+	cfg    JWTConfig
+	parser *jwt.Parser
+}
+
+// NewJWTAuthenticator returns an authenticator of the tokens signed with
+// the key set.
+func NewJWTAuthenticator(keys KeySet, cfg JWTConfig) (*JWTAuthenticator, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("jwt issuer and audience are required")
+	}
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = DefaultRolesClaim
+	}
+	if cfg.RoleScopes == nil {
+		cfg.RoleScopes = DefaultRoleScopes
+	}
+	return &JWTAuthenticator{
+		keys: keys,
+		cfg:  cfg,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(signingMethods),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(cfg.Leeway),
+		),
+	}, nil
+}
+
+// Authenticate returns the principal of a valid token. The subject is the
+// sub claim and the scopes are granted by the roles of the token.
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	var keyErr error
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := a.keys.Key(ctx, kid)
+		if err != nil && !errors.Is(err, ErrUnknownKey) {
+			keyErr = err
+		}
+		return key, err
+	})
+	if keyErr != nil {
+		return nil, fmt.Errorf("jwt signing key: %w", keyErr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	sub, err := claims.GetSubject()
+	if err != nil || sub == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+	p := &Principal{Subject: sub}
+	for _, c := range []string{"preferred_username", "name"} {
+		if name, ok := claims[c].(string); ok && name != "" {
+			p.Name = name
+			break
+		}
+	}
+	for _, role := range claimStrings(claims, a.cfg.RolesClaim) {
+		for _, s := range a.cfg.RoleScopes[role] {
+			if !p.HasScope(s) {
+				p.Scopes = append(p.Scopes, s)
+			}
+		}
+	}
+	return p, nil
+}
+
+// claimStrings returns the values of a dotted claim path, either an array
+// of strings or a space separated string.
+func claimStrings(claims map[string]any, path string) []string {
+	var v any = claims
+	for name := range strings.SplitSeq(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[name]
+	}
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// ParseRoleScopes parses a role to scopes mapping written as
+// "role=scope scope;role=scope".
+func ParseRoleScopes(s string) (map[string][]Scope, error) {
+	m := make(map[string][]Scope)
+	for entry := range strings.SplitSeq(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		role, names, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(role) == "" {
+			return nil, fmt.Errorf("invalid role mapping: %s", entry)
+		}
+		scopes, err := ParseScopes(strings.Fields(names))
+		if err != nil {
+			return nil, fmt.Errorf("role %s: %w", role, err)
+		}
+		m[strings.TrimSpace(role)] = scopes
+	}
+	return m, nil
+}
+
+// Authenticator validates the credentials of a request.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credentials string) (*Principal, error)
+}
+
+// Chain tries the authenticators in order and returns the first principal.
+// The credentials are invalid when all of them reject it.
+type Chain []Authenticator
+
+// Authenticate implements Authenticator.
+func (c Chain) Authenticate(ctx context.Context, credentials string) (*Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(ctx, credentials)
+		if err == nil {
+			return p, nil
+		}
+		if !errors.Is(err, ErrInvalidCredentials) {
+			return nil, err
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
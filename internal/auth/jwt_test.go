@@ -0,0 +1,290 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKeys are locally generated signing keys and their JWKS document.
+type testKeys struct {
+	rsa  *rsa.PrivateKey
+	ec   *ecdsa.PrivateKey
+	jwks []byte
+}
+
+func newTestKeys(tb testing.TB) testKeys {
+	tb.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(tb, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(tb, err)
+
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		{"kty": "oct", "kid": "hmac", "k": b64([]byte("secret"))},
+	}})
+	require.NoError(tb, err)
+	return testKeys{rsa: rsaKey, ec: ecKey, jwks: jwks}
+}
+
+func sign(tb testing.TB, method jwt.SigningMethod, kid string, key crypto.PrivateKey, claims jwt.MapClaims) string {
+	tb.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(key)
+	require.NoError(tb, err)
+	return s
+}
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	keys := newTestKeys(t)
+	keySet, err := auth.ParseJWKS(keys.jwks)
+	require.NoError(t, err)
+	a, err := auth.NewJWTAuthenticator(auth.StaticKeySet(keySet), auth.JWTConfig{
+		Issuer:   "https://issuer.example.com",
+		Audience: "newsapi",
+	})
+	require.NoError(t, err)
+
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":   "https://issuer.example.com",
+			"aud":   []string{"gateway", "newsapi"},
+			"sub":   "user-1",
+			"name":  "Clark Kent",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": []string{"reader", "writer", "unknown"},
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	testCases := []struct {
+		name           string
+		token          string
+		expectedErr    bool
+		expectedScopes []auth.Scope
+	}{
+		{
+			name:           "rsa",
+			token:          sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims(nil)),
+			expectedScopes: []auth.Scope{auth.ScopeNewsRead, auth.ScopeNewsWrite},
+		},
+		{
+			name:           "ec with space separated roles",
+			token:          sign(t, jwt.SigningMethodES256, "ec", keys.ec, claims(jwt.MapClaims{"roles": "admin"})),
+			expectedScopes: []auth.Scope{auth.ScopeNewsAdmin},
+		},
+		{
+			name:  "no roles",
+			token: sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims(jwt.MapClaims{"roles": nil})),
+		},
+		{
+			name:        "wrong issuer",
+			token:       sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims(jwt.MapClaims{"iss": "https://other.example.com"})),
+			expectedErr: true,
+		},
+		{
+			name:        "wrong audience",
+			token:       sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims(jwt.MapClaims{"aud": "other"})),
+			expectedErr: true,
+		},
+		{
+			name:        "expired",
+			token:       sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})),
+			expectedErr: true,
+		},
+		{
+			name:        "no expiry",
+			token:       sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims(jwt.MapClaims{"exp": nil})),
+			expectedErr: true,
+		},
+		{
+			name:        "no subject",
+			token:       sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims(jwt.MapClaims{"sub": nil})),
+			expectedErr: true,
+		},
+		{
+			name:        "unknown key",
+			token:       sign(t, jwt.SigningMethodRS256, "other", keys.rsa, claims(nil)),
+			expectedErr: true,
+		},
+		{
+			name:        "symmetric algorithm",
+			token:       sign(t, jwt.SigningMethodHS256, "hmac", []byte("secret"), claims(nil)),
+			expectedErr: true,
+		},
+		{
+			name:        "key of another type",
+			token:       sign(t, jwt.SigningMethodES256, "rsa", keys.ec, claims(nil)),
+			expectedErr: true,
+		},
+		{
+			name:        "not a token",
+			token:       "nk_key",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := a.Authenticate(context.Background(), tc.token)
+
+			if tc.expectedErr {
+				assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", p.Subject)
+			assert.Equal(t, "Clark Kent", p.Name)
+			assert.Equal(t, tc.expectedScopes, p.Scopes)
+		})
+	}
+}
+
+func TestJWTAuthenticator_NestedRolesClaim(t *testing.T) {
+	keys := newTestKeys(t)
+	keySet, err := auth.ParseJWKS(keys.jwks)
+	require.NoError(t, err)
+	a, err := auth.NewJWTAuthenticator(auth.StaticKeySet(keySet), auth.JWTConfig{
+		Issuer:     "issuer",
+		Audience:   "newsapi",
+		RolesClaim: "realm_access.roles",
+		RoleScopes: map[string][]auth.Scope{"editor": {auth.ScopeNewsWrite}},
+	})
+	require.NoError(t, err)
+	token := sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, jwt.MapClaims{
+		"iss":          "issuer",
+		"aud":          "newsapi",
+		"sub":          "user-1",
+		"exp":          time.Now().Add(time.Hour).Unix(),
+		"realm_access": map[string]any{"roles": []string{"editor"}},
+	})
+
+	p, err := a.Authenticate(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, []auth.Scope{auth.ScopeNewsWrite}, p.Scopes)
+}
+
+func TestNewKeySet(t *testing.T) {
+	keys := newTestKeys(t)
+	token := sign(t, jwt.SigningMethodES256, "ec", keys.ec, jwt.MapClaims{
+		"iss": "issuer",
+		"aud": "newsapi",
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	var fetches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		_, _ = w.Write(keys.jwks)
+	}))
+	defer srv.Close()
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(file, keys.jwks, 0o600))
+
+	for _, location := range []string{srv.URL, file} {
+		t.Run(location, func(t *testing.T) {
+			keySet, err := auth.NewKeySet(location, time.Hour)
+			require.NoError(t, err)
+			a, err := auth.NewJWTAuthenticator(keySet, auth.JWTConfig{Issuer: "issuer", Audience: "newsapi"})
+			require.NoError(t, err)
+
+			for range 2 {
+				_, err = a.Authenticate(context.Background(), token)
+				require.NoError(t, err)
+			}
+		})
+	}
+	assert.Equal(t, 1, fetches, "remote keys must be cached")
+}
+
+func TestNewKeySet_Unavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, err := auth.NewKeySet(srv.URL, time.Hour)
+
+	assert.ErrorContains(t, err, "unexpected status")
+}
+
+func TestRemoteKeySet_Key_CallerGone(t *testing.T) {
+	// Arrange
+	keys := newTestKeys(t)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		_, _ = w.Write(keys.jwks)
+	}))
+	defer srv.Close()
+	keySet := auth.NewRemoteKeySet(srv.URL, nil, time.Hour)
+
+	// Act
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, cancelledErr := keySet.Key(ctx, "ec")
+	close(release)
+	key, err := keySet.Key(context.Background(), "ec")
+
+	// Assert
+	assert.ErrorIs(t, cancelledErr, context.DeadlineExceeded)
+	require.NoError(t, err)
+	assert.NotNil(t, key)
+}
+
+func TestChain_Authenticate(t *testing.T) {
+	keys := newTestKeys(t)
+	keySet, err := auth.ParseJWKS(keys.jwks)
+	require.NoError(t, err)
+	a, err := auth.NewJWTAuthenticator(auth.StaticKeySet(keySet), auth.JWTConfig{Issuer: "issuer", Audience: "newsapi"})
+	require.NoError(t, err)
+	chain := auth.Chain{a, a}
+
+	_, err = chain.Authenticate(context.Background(), "nk_key")
+
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+}
+
+func TestParseRoleScopes(t *testing.T) {
+	m, err := auth.ParseRoleScopes("reader=news:read; editor=news:read news:write;")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]auth.Scope{
+		"reader": {auth.ScopeNewsRead},
+		"editor": {auth.ScopeNewsRead, auth.ScopeNewsWrite},
+	}, m)
+
+	_, err = auth.ParseRoleScopes("reader")
+	assert.Error(t, err)
+	_, err = auth.ParseRoleScopes("reader=news:delete")
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+// Package auth authenticates the clients of the API and checks what they
+// are allowed to do.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Scope is a permission granted to a client.
+type Scope string
+
+// Scopes of the news API.
+const (
+	// ScopeNewsRead allows reading and searching news.
+	ScopeNewsRead Scope = "news:read"
+	// ScopeNewsWrite allows creating, updating, deleting and restoring news.
+	ScopeNewsWrite Scope = "news:write"
+	// ScopeNewsAdmin allows everything, including purging news.
+	ScopeNewsAdmin Scope = "news:admin"
+)
+
+// Scopes are all the known scopes.
+var Scopes = []Scope{ScopeNewsRead, ScopeNewsWrite, ScopeNewsAdmin}
+
+// ParseScopes parses and deduplicates the scope names.
+func ParseScopes(names []string) ([]Scope, error) {
+	var scopes []Scope
+	for _, name := range names {
+		s := Scope(name)
+		if !slices.Contains(Scopes, s) {
+			return nil, fmt.Errorf("unknown scope: %s", name)
+		}
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes, nil
+}
+
+// Principal is an authenticated client.
+type Principal struct {
+	// Subject identifies the client, e.g. the id of its API key.
+	Subject string
+	// Name is a human readable name of the client.
+	Name   string
+	Scopes []Scope
+}
+
+// HasScope reports whether the principal was granted the scope. The admin
+// scope implies all the others and the write scope implies read.
+func (p *Principal) HasScope(s Scope) bool {
+	if p == nil {
+		return false
+	}
+	switch {
+	case slices.Contains(p.Scopes, s), slices.Contains(p.Scopes, ScopeNewsAdmin):
+		return true
+	case s == ScopeNewsRead:
+		return slices.Contains(p.Scopes, ScopeNewsWrite)
+	default:
+		return false
+	}
+}
+
+// ctxKey for the principal.
+type ctxKey struct{}
+
+// NewContext returns the context enriched with the principal.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext returns the principal of the context, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,55 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipal_HasScope(t *testing.T) {
+	testCases := []struct {
+		name      string
+		principal *auth.Principal
+		scope     auth.Scope
+		expected  bool
+	}{
+		{name: "nil principal", scope: auth.ScopeNewsRead},
+		{name: "granted", principal: &auth.Principal{Scopes: []auth.Scope{auth.ScopeNewsRead}}, scope: auth.ScopeNewsRead, expected: true},
+		{name: "missing", principal: &auth.Principal{Scopes: []auth.Scope{auth.ScopeNewsRead}}, scope: auth.ScopeNewsWrite},
+		{name: "write implies read", principal: &auth.Principal{Scopes: []auth.Scope{auth.ScopeNewsWrite}}, scope: auth.ScopeNewsRead, expected: true},
+		{name: "write does not imply admin", principal: &auth.Principal{Scopes: []auth.Scope{auth.ScopeNewsWrite}}, scope: auth.ScopeNewsAdmin},
+		{name: "admin implies write", principal: &auth.Principal{Scopes: []auth.Scope{auth.ScopeNewsAdmin}}, scope: auth.ScopeNewsWrite, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.principal.HasScope(tc.scope))
+		})
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		names       []string
+		expected    []auth.Scope
+		expectedErr bool
+	}{
+		{name: "valid", names: []string{"news:read", "news:write", "news:read"}, expected: []auth.Scope{auth.ScopeNewsRead, auth.ScopeNewsWrite}},
+		{name: "unknown", names: []string{"news:read", "news:delete"}, expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scopes, err := auth.ParseScopes(tc.names)
+
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, scopes)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+INSERT INTO api_keys (id, name, prefix, hash, scopes, revoked_at)
+VALUES (
+  '0b3c7c3e-5f4a-4d59-9d1f-2a8f3c1d7e01',
+  'revoked',
+  'nk_rev',
+  '9c3f4d7bcf6d2b8bb4b1f1a4d9ef7a6b5b0d7c0e3f5f8a3e2c1b0a9f8e7d6c5b',
+  ARRAY ['news:read'],
+  NOW());
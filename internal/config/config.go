@@ -0,0 +1,254 @@
+// Package config loads the configuration of the commands from a YAML file,
+// the environment and the command line flags.
+//
+// Each setting is looked up in the following order, the first source
+// setting it wins:
+//
+//  1. the command line flag, e.g. -database-host;
+//  2. the environment variable, e.g. DATABASE_HOST;
+//  3. the YAML file given with -config or CONFIG_FILE, e.g. database.host;
+//  4. the default value.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/idempotency"
+	"github.com/prashsamosa/newsapi/internal/tlsconfig"
+)
+
+// sslModes are the SSL modes supported by the PostgreSQL driver.
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Config is the configuration of the commands.
+type Config struct {
+	Server      Server      `yaml:"server"`
+	Database    Database    `yaml:"database"`
+	Trash       Trash       `yaml:"trash"`
+	Scheduler   Scheduler   `yaml:"scheduler"`
+	Idempotency Idempotency `yaml:"idempotency"`
+	JWT         JWT         `yaml:"jwt"`
+	Tracing     Tracing     `yaml:"tracing"`
+	TLS         TLS         `yaml:"tls"`
+}
+
+// Server configures the HTTP server.
+type Server struct {
+	Addr              string        `yaml:"addr"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	// ShutdownDelay is the time the server keeps serving once the readiness
+	// probe fails, before it stops accepting connections.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+	// ShutdownTimeout bounds the time taken to drain the connections.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// Database configures the PostgreSQL connection pool.
+type Database struct {
+	Host         string `yaml:"host"`
+	Port         string `yaml:"port"`
+	Name         string `yaml:"name"`
+	User         string `yaml:"user"`
+	Password     Secret `yaml:"password"`
+	SSLMode      string `yaml:"ssl_mode"`
+	MaxOpenConns int    `yaml:"max_open_conns"`
+	MaxIdleConns int    `yaml:"max_idle_conns"`
+	Debug        bool   `yaml:"debug"`
+}
+
+// Trash configures the purge of the deleted news.
+type Trash struct {
+	// Retention is how long the deleted news are kept, 0 disables the purge.
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// Scheduler configures the publishing and expiry of the scheduled news.
+type Scheduler struct {
+	Interval time.Duration `yaml:"interval"`
+}
+
+// Idempotency configures the idempotency keys.
+type Idempotency struct {
+	KeyTTL time.Duration `yaml:"key_ttl"`
+	// Lease is how long a request in progress holds its key, a retry after
+	// it takes the key over.
+	Lease time.Duration `yaml:"lease"`
+}
+
+// JWT configures the authentication with JWTs, disabled when JWKS is empty.
+type JWT struct {
+	// JWKS is the URL or the path of the JSON Web Key Set.
+	JWKS        string        `yaml:"jwks"`
+	JWKSRefresh time.Duration `yaml:"jwks_refresh"`
+	Issuer      string        `yaml:"issuer"`
+	Audience    string        `yaml:"audience"`
+	RolesClaim  string        `yaml:"roles_claim"`
+	// RoleScopes maps the roles to the scopes, in the auth.ParseRoleScopes
+	// format.
+	RoleScopes string        `yaml:"role_scopes"`
+	Leeway     time.Duration `yaml:"leeway"`
+}
+
+// Tracing configures the export of the traces.
+type Tracing struct {
+	// Exporter is one of the tracing exporters, tracing is disabled when
+	// empty.
+	Exporter string `yaml:"exporter"`
+}
+
+// TLS configures the HTTPS server, enabled when CertFile is set.
+type TLS struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ClientCAFile is the CA bundle verifying the client certificates, mTLS
+	// is disabled when empty.
+	ClientCAFile string `yaml:"client_ca_file"`
+	// ClientAuth is require or optional.
+	ClientAuth string `yaml:"client_auth"`
+	// MinVersion is 1.2 or 1.3.
+	MinVersion string `yaml:"min_version"`
+	// CipherSuites is a comma separated list of TLS 1.2 cipher suites, the
+	// Go defaults when empty.
+	CipherSuites string `yaml:"cipher_suites"`
+	// ReloadInterval is how often the files are checked for changes.
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+// Secret is a value redacted when printed or logged.
+type Secret string
+
+// String implements fmt.Stringer.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return "[REDACTED]"
+}
+
+// Default returns the default configuration.
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Addr:              ":8080",
+			ReadHeaderTimeout: 3 * time.Second,
+			ShutdownDelay:     5 * time.Second,
+			ShutdownTimeout:   5 * time.Second,
+		},
+		Database: Database{
+			Port:         "5432",
+			SSLMode:      "disable",
+			MaxOpenConns: 20,
+			MaxIdleConns: 10,
+		},
+		Trash: Trash{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Scheduler: Scheduler{
+			Interval: time.Minute,
+		},
+		Idempotency: Idempotency{
+			KeyTTL: idempotency.DefaultTTL,
+			Lease:  idempotency.DefaultLease,
+		},
+		JWT: JWT{
+			JWKSRefresh: time.Hour,
+			Leeway:      30 * time.Second,
+		},
+		TLS: TLS{
+			ClientAuth:     tlsconfig.ClientAuthRequire,
+			MinVersion:     "1.2",
+			ReloadInterval: time.Minute,
+		},
+	}
+}
+
+// Validate returns the invalid settings of the configuration.
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+	positive := func(key string, d time.Duration) {
+		if d <= 0 {
+			invalid(key, "must be positive: %s", d)
+		}
+	}
+
+	if c.Server.Addr == "" {
+		invalid("server.addr", "is empty")
+	}
+	positive("server.read_header_timeout", c.Server.ReadHeaderTimeout)
+	positive("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	if c.Server.ShutdownDelay < 0 {
+		invalid("server.shutdown_delay", "must not be negative: %s", c.Server.ShutdownDelay)
+	}
+
+	if c.Database.Host == "" {
+		invalid("database.host", "is empty")
+	}
+	if port, err := strconv.Atoi(c.Database.Port); err != nil || port < 1 || port > 65535 {
+		invalid("database.port", "must be a port number: %q", c.Database.Port)
+	}
+	if c.Database.Name == "" {
+		invalid("database.name", "is empty")
+	}
+	if c.Database.User == "" {
+		invalid("database.user", "is empty")
+	}
+	if !slices.Contains(sslModes, c.Database.SSLMode) {
+		invalid("database.ssl_mode", "must be one of %v: %q", sslModes, c.Database.SSLMode)
+	}
+	if c.Database.MaxOpenConns < 1 {
+		invalid("database.max_open_conns", "must be positive: %d", c.Database.MaxOpenConns)
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		invalid("database.max_idle_conns", "must be between 0 and database.max_open_conns: %d", c.Database.MaxIdleConns)
+	}
+
+	if c.Trash.Retention < 0 {
+		invalid("trash.retention", "must not be negative: %s", c.Trash.Retention)
+	}
+	positive("trash.purge_interval", c.Trash.PurgeInterval)
+	positive("scheduler.interval", c.Scheduler.Interval)
+	positive("idempotency.key_ttl", c.Idempotency.KeyTTL)
+	positive("idempotency.lease", c.Idempotency.Lease)
+
+	if c.JWT.JWKS != "" {
+		positive("jwt.jwks_refresh", c.JWT.JWKSRefresh)
+		if c.JWT.Leeway < 0 {
+			invalid("jwt.leeway", "must not be negative: %s", c.JWT.Leeway)
+		}
+		if c.JWT.RoleScopes != "" {
+			if _, err := auth.ParseRoleScopes(c.JWT.RoleScopes); err != nil {
+				invalid("jwt.role_scopes", "%v", err)
+			}
+		}
+	}
+
+	if c.TLS.CertFile != "" || c.TLS.KeyFile != "" {
+		if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+			invalid("tls", "cert_file and key_file must be set together")
+		}
+		if _, err := tlsconfig.ParseClientAuth(c.TLS.ClientAuth); err != nil {
+			invalid("tls.client_auth", "%v", err)
+		}
+		if _, err := tlsconfig.ParseVersion(c.TLS.MinVersion); err != nil {
+			invalid("tls.min_version", "%v", err)
+		}
+		if _, err := tlsconfig.ParseCipherSuites(c.TLS.CipherSuites); err != nil {
+			invalid("tls.cipher_suites", "%v", err)
+		}
+		positive("tls.reload_interval", c.TLS.ReloadInterval)
+	} else if c.TLS.ClientCAFile != "" {
+		invalid("tls.client_ca_file", "requires tls.cert_file and tls.key_file")
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,173 @@
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// required are the settings without a default value.
+const required = `
+database:
+  host: localhost
+  name: news
+  user: news
+`
+
+// writeFile writes the configuration file and returns its path.
+func writeFile(tb testing.TB, content string) string {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "config.yaml")
+	require.NoError(tb, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// env returns the lookup of the environment variables.
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name          string
+		file          string
+		env           map[string]string
+		flags         map[string]string
+		expectedError string
+		assert        func(t *testing.T, cfg *config.Config)
+	}{
+		{
+			name: "defaults",
+			file: required,
+			assert: func(t *testing.T, cfg *config.Config) {
+				assert.Equal(t, ":8080", cfg.Server.Addr)
+				assert.Equal(t, 3*time.Second, cfg.Server.ReadHeaderTimeout)
+				assert.Equal(t, "5432", cfg.Database.Port)
+				assert.Equal(t, "disable", cfg.Database.SSLMode)
+				assert.Equal(t, 20, cfg.Database.MaxOpenConns)
+				assert.Equal(t, 10, cfg.Database.MaxIdleConns)
+			},
+		},
+		{
+			name: "file overrides the defaults",
+			file: required + "  max_open_conns: 50\nserver:\n  shutdown_timeout: 30s\n",
+			assert: func(t *testing.T, cfg *config.Config) {
+				assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+				assert.Equal(t, 30*time.Second, cfg.Server.ShutdownTimeout)
+			},
+		},
+		{
+			name: "env overrides the file",
+			file: required,
+			env:  map[string]string{"DATABASE_HOST": "db", "SCHEDULER_INTERVAL": "10s", "DATABASE_USER": ""},
+			assert: func(t *testing.T, cfg *config.Config) {
+				assert.Equal(t, "db", cfg.Database.Host)
+				assert.Equal(t, "news", cfg.Database.User)
+				assert.Equal(t, 10*time.Second, cfg.Scheduler.Interval)
+			},
+		},
+		{
+			name:  "flags override the env",
+			file:  required,
+			env:   map[string]string{"DATABASE_HOST": "db", "SHUTDOWN_DELAY": "10s"},
+			flags: map[string]string{"database-host": "flag-db", "server-shutdown-delay": "0s"},
+			assert: func(t *testing.T, cfg *config.Config) {
+				assert.Equal(t, "flag-db", cfg.Database.Host)
+				assert.Equal(t, time.Duration(0), cfg.Server.ShutdownDelay)
+			},
+		},
+		{
+			name:          "unknown file key",
+			file:          required + "  hots: localhost\n",
+			expectedError: "field hots not found",
+		},
+		{
+			name:          "invalid env value",
+			file:          required,
+			env:           map[string]string{"DATABASE_MAX_OPEN_CONNS": "many"},
+			expectedError: "DATABASE_MAX_OPEN_CONNS",
+		},
+		{
+			name:          "invalid flag value",
+			file:          required,
+			flags:         map[string]string{"trash-retention": "forever"},
+			expectedError: "-trash-retention",
+		},
+		{
+			name:          "missing required settings",
+			expectedError: "database.host: is empty",
+		},
+		{
+			name:          "invalid settings",
+			file:          required,
+			env:           map[string]string{"DATABASE_SSL_MODE": "always", "DATABASE_MAX_IDLE_CONNS": "30"},
+			expectedError: "database.ssl_mode",
+		},
+		{
+			name:          "invalid role scopes",
+			file:          required,
+			env:           map[string]string{"JWT_JWKS": "jwks.json", "JWT_ROLE_SCOPES": "admin"},
+			expectedError: "jwt.role_scopes",
+		},
+		{
+			name:          "tls key without certificate",
+			file:          required,
+			env:           map[string]string{"TLS_KEY_FILE": "tls.key"},
+			expectedError: "cert_file and key_file must be set together",
+		},
+		{
+			name:          "invalid tls settings",
+			file:          required,
+			env:           map[string]string{"TLS_CERT_FILE": "tls.crt", "TLS_KEY_FILE": "tls.key", "TLS_MIN_VERSION": "1.0"},
+			expectedError: "tls.min_version",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			path := ""
+			if tc.file != "" {
+				path = writeFile(t, tc.file)
+			}
+
+			// Act
+			cfg, err := config.Load(path, env(tc.env), tc.flags)
+
+			// Assert
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			tc.assert(t, cfg)
+		})
+	}
+}
+
+func TestConfig_LogValue(t *testing.T) {
+	// Arrange
+	cfg, err := config.Load(writeFile(t, required+"  password: hunter2\n"), env(nil), nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	// Act
+	log.Info("config loaded", "config", cfg)
+
+	// Assert
+	assert.Equal(t, config.Secret("hunter2"), cfg.Database.Password)
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.Contains(t, buf.String(), `"database.password":"[REDACTED]"`)
+	assert.Contains(t, buf.String(), `"database.host":"localhost"`)
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv is the environment variable holding the path of the
+// configuration file, when the -config flag is not set.
+const FileEnv = "CONFIG_FILE"
+
+// setting is a value of the configuration, which can be set by a key of the
+// file, an environment variable and a flag.
+type setting struct {
+	key   string
+	env   string
+	usage string
+	value func(c *Config) flag.Value
+}
+
+// flagName is the name of the flag of the setting: database.ssl_mode is
+// set with -database-ssl-mode.
+func (s setting) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(s.key)
+}
+
+// settings are all the values of the configuration. The environment
+// variables predating the configuration file keep their names.
+var settings = []setting{
+	{key: "server.addr", env: "SERVER_ADDR", usage: "address the server listens on",
+		value: func(c *Config) flag.Value { return stringValue(&c.Server.Addr) }},
+	{key: "server.read_header_timeout", env: "SERVER_READ_HEADER_TIMEOUT", usage: "time allowed to read the request headers",
+		value: func(c *Config) flag.Value { return durationValue(&c.Server.ReadHeaderTimeout) }},
+	{key: "server.shutdown_delay", env: "SHUTDOWN_DELAY", usage: "time the server keeps serving once the readiness probe fails",
+		value: func(c *Config) flag.Value { return durationValue(&c.Server.ShutdownDelay) }},
+	{key: "server.shutdown_timeout", env: "SERVER_SHUTDOWN_TIMEOUT", usage: "time allowed to drain the connections on shutdown",
+		value: func(c *Config) flag.Value { return durationValue(&c.Server.ShutdownTimeout) }},
+
+	{key: "database.host", env: "DATABASE_HOST", usage: "database host",
+		value: func(c *Config) flag.Value { return stringValue(&c.Database.Host) }},
+	{key: "database.port", env: "DATABASE_PORT", usage: "database port",
+		value: func(c *Config) flag.Value { return stringValue(&c.Database.Port) }},
+	{key: "database.name", env: "DATABASE_NAME", usage: "database name",
+		value: func(c *Config) flag.Value { return stringValue(&c.Database.Name) }},
+	{key: "database.user", env: "DATABASE_USER", usage: "database user",
+		value: func(c *Config) flag.Value { return stringValue(&c.Database.User) }},
+	{key: "database.password", env: "DATABASE_PASSWORD", usage: "database password",
+		value: func(c *Config) flag.Value { return stringValue(&c.Database.Password) }},
+	{key: "database.ssl_mode", env: "DATABASE_SSL_MODE", usage: "database SSL mode",
+		value: func(c *Config) flag.Value { return stringValue(&c.Database.SSLMode) }},
+	{key: "database.max_open_conns", env: "DATABASE_MAX_OPEN_CONNS", usage: "maximum number of open database connections",
+		value: func(c *Config) flag.Value { return intValue(&c.Database.MaxOpenConns) }},
+	{key: "database.max_idle_conns", env: "DATABASE_MAX_IDLE_CONNS", usage: "maximum number of idle database connections",
+		value: func(c *Config) flag.Value { return intValue(&c.Database.MaxIdleConns) }},
+	{key: "database.debug", env: "DATABASE_DEBUG", usage: "log the database queries",
+		value: func(c *Config) flag.Value { return boolValue(&c.Database.Debug) }},
+
+	{key: "trash.retention", env: "TRASH_RETENTION", usage: "time the deleted news are kept, 0 disables the purge",
+		value: func(c *Config) flag.Value { return durationValue(&c.Trash.Retention) }},
+	{key: "trash.purge_interval", env: "TRASH_PURGE_INTERVAL", usage: "interval of the purge of the trash",
+		value: func(c *Config) flag.Value { return durationValue(&c.Trash.PurgeInterval) }},
+	{key: "scheduler.interval", env: "SCHEDULER_INTERVAL", usage: "interval of the publishing and expiry of the scheduled news",
+		value: func(c *Config) flag.Value { return durationValue(&c.Scheduler.Interval) }},
+	{key: "idempotency.key_ttl", env: "IDEMPOTENCY_KEY_TTL", usage: "lifetime of the idempotency keys",
+		value: func(c *Config) flag.Value { return durationValue(&c.Idempotency.KeyTTL) }},
+	{key: "idempotency.lease", env: "IDEMPOTENCY_LEASE", usage: "time a request in progress holds its idempotency key",
+		value: func(c *Config) flag.Value { return durationValue(&c.Idempotency.Lease) }},
+
+	{key: "jwt.jwks", env: "JWT_JWKS", usage: "URL or path of the JSON Web Key Set, JWTs are rejected when empty",
+		value: func(c *Config) flag.Value { return stringValue(&c.JWT.JWKS) }},
+	{key: "jwt.jwks_refresh", env: "JWT_JWKS_REFRESH", usage: "cache duration of a remote JSON Web Key Set",
+		value: func(c *Config) flag.Value { return durationValue(&c.JWT.JWKSRefresh) }},
+	{key: "jwt.issuer", env: "JWT_ISSUER", usage: "required issuer of the JWTs",
+		value: func(c *Config) flag.Value { return stringValue(&c.JWT.Issuer) }},
+	{key: "jwt.audience", env: "JWT_AUDIENCE", usage: "required audience of the JWTs",
+		value: func(c *Config) flag.Value { return stringValue(&c.JWT.Audience) }},
+	{key: "jwt.roles_claim", env: "JWT_ROLES_CLAIM", usage: "claim holding the roles of the JWTs",
+		value: func(c *Config) flag.Value { return stringValue(&c.JWT.RolesClaim) }},
+	{key: "jwt.role_scopes", env: "JWT_ROLE_SCOPES", usage: "scopes granted to the roles, e.g. reader=news:read;writer=news:write",
+		value: func(c *Config) flag.Value { return stringValue(&c.JWT.RoleScopes) }},
+	{key: "jwt.leeway", env: "JWT_LEEWAY", usage: "clock skew allowed when checking the JWTs",
+		value: func(c *Config) flag.Value { return durationValue(&c.JWT.Leeway) }},
+
+	{key: "tracing.exporter", env: "OTEL_TRACES_EXPORTER", usage: "traces exporter: none, otlp, stdout or memory",
+		value: func(c *Config) flag.Value { return stringValue(&c.Tracing.Exporter) }},
+
+	{key: "tls.cert_file", env: "TLS_CERT_FILE", usage: "certificate of the server, HTTPS is served when set",
+		value: func(c *Config) flag.Value { return stringValue(&c.TLS.CertFile) }},
+	{key: "tls.key_file", env: "TLS_KEY_FILE", usage: "private key of the certificate",
+		value: func(c *Config) flag.Value { return stringValue(&c.TLS.KeyFile) }},
+	{key: "tls.client_ca_file", env: "TLS_CLIENT_CA_FILE", usage: "CA bundle verifying the client certificates, mTLS is enabled when set",
+		value: func(c *Config) flag.Value { return stringValue(&c.TLS.ClientCAFile) }},
+	{key: "tls.client_auth", env: "TLS_CLIENT_AUTH", usage: "client certificates policy: require or optional",
+		value: func(c *Config) flag.Value { return stringValue(&c.TLS.ClientAuth) }},
+	{key: "tls.min_version", env: "TLS_MIN_VERSION", usage: "minimum TLS version: 1.2 or 1.3",
+		value: func(c *Config) flag.Value { return stringValue(&c.TLS.MinVersion) }},
+	{key: "tls.cipher_suites", env: "TLS_CIPHER_SUITES", usage: "comma separated TLS 1.2 cipher suites, the Go defaults when empty",
+		value: func(c *Config) flag.Value { return stringValue(&c.TLS.CipherSuites) }},
+	{key: "tls.reload_interval", env: "TLS_RELOAD_INTERVAL", usage: "interval of the checks for changed certificates",
+		value: func(c *Config) flag.Value { return durationValue(&c.TLS.ReloadInterval) }},
+}
+
+// Parse parses the flags of the command from the arguments, and loads the
+// configuration with the file and the environment. It returns the
+// arguments left after the flags.
+func Parse(name string, args []string) (*Config, []string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	file := fs.String("config", "", fmt.Sprintf("path of the YAML configuration file (env %s)", FileEnv))
+	flags := make(map[string]string)
+	defaults := Default()
+	for _, s := range settings {
+		fs.Var(&flagValue{name: s.flagName(), flags: flags, def: s.value(defaults)},
+			s.flagName(), fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	path := *file
+	if path == "" {
+		path = os.Getenv(FileEnv)
+	}
+	cfg, err := Load(path, os.LookupEnv, flags)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+// Load loads the configuration from the file, when the path is not empty,
+// then from the environment and last from the flags, keyed by their name.
+// The configuration is validated once loaded.
+func Load(path string, lookupEnv func(string) (string, bool), flags map[string]string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, s := range settings {
+		if v, ok := lookupEnv(s.env); ok && v != "" {
+			if err := s.value(cfg).Set(v); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+	for _, s := range settings {
+		if v, ok := flags[s.flagName()]; ok {
+			if err := s.value(cfg).Set(v); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", s.flagName(), err))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadFile decodes the YAML file into the configuration. Unknown keys are
+// rejected.
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decode config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// LogValue implements slog.LogValuer. The secrets are redacted.
+func (c *Config) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(settings))
+	for _, s := range settings {
+		attrs = append(attrs, slog.String(s.key, s.value(c).String()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// flagValue records the value of a flag set on the command line, so that
+// it is applied after the file and the environment.
+type flagValue struct {
+	name  string
+	flags map[string]string
+	def   flag.Value
+}
+
+// String implements flag.Value. It returns the default value of the
+// setting.
+func (f *flagValue) String() string {
+	if f.def == nil {
+		return ""
+	}
+	return f.def.String()
+}
+
+// Set implements flag.Value.
+func (f *flagValue) Set(v string) error {
+	f.flags[f.name] = v
+	return nil
+}
+
+// value is a flag.Value setting a field of the configuration.
+type value[T any] struct {
+	p     *T
+	parse func(string) (T, error)
+}
+
+func (v value[T]) Set(s string) error {
+	x, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	*v.p = x
+	return nil
+}
+
+func (v value[T]) String() string {
+	return fmt.Sprint(*v.p)
+}
+
+func stringValue[T ~string](p *T) flag.Value {
+	return value[T]{p: p, parse: func(s string) (T, error) { return T(s), nil }}
+}
+
+func intValue(p *int) flag.Value {
+	return value[int]{p: p, parse: strconv.Atoi}
+}
+
+func boolValue(p *bool) flag.Value {
+	return value[bool]{p: p, parse: strconv.ParseBool}
+}
+
+func durationValue(p *time.Duration) flag.Value {
+	return value[time.Duration]{p: p, parse: time.ParseDuration}
+}
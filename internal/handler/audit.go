@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/logger"
+	"github.com/prashsamosa/newsapi/internal/news"
+)
+
+// auditQueryParams are the query parameters accepted by the audit log.
+var auditQueryParams = []string{"news_id", "actor", "action", "since", "until", "limit", "offset"}
+
+// newsAuditQueryParams are the query parameters accepted by the audit log
+// of a news.
+var newsAuditQueryParams = []string{"actor", "action", "since", "until", "limit", "offset"}
+
+// GetAudit handler.
+func GetAudit(as AuditStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+
+		f, err := parseAuditFilter(r.URL.Query(), auditQueryParams)
+		if err != nil {
+			log.Error("invalid query parameters", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidQuery, err)
+			return
+		}
+		writeAudit(w, r, as, f)
+	}
+}
+
+// GetNewsAudit handler.
+func GetNewsAudit(as AuditStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
+		if err != nil {
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
+			return
+		}
+
+		f, err := parseAuditFilter(r.URL.Query(), newsAuditQueryParams)
+		if err != nil {
+			log.Error("invalid query parameters", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidQuery, err)
+			return
+		}
+		f.NewsID = newsUUID
+		writeAudit(w, r, as, f)
+	}
+}
+
+// writeAudit writes the audit log entries matching the filter.
+func writeAudit(w http.ResponseWriter, r *http.Request, as AuditStorer, f news.AuditFilter) {
+	log := logger.FromContext(r.Context())
+	entries, total, err := as.FindAudit(r.Context(), f)
+	if err != nil {
+		log.Error("failed to fetch the audit log", "error", err)
+		writeStoreError(w, r, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(AuditResponse{Entries: entries, TotalHint: total}); err != nil {
+		log.Error("failed to write response", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseAuditFilter builds the audit log filter from the query string,
+// which can only hold the allowed parameters.
+func parseAuditFilter(q url.Values, allowed []string) (f news.AuditFilter, errs error) {
+	errs = unsupportedParams(q, allowed)
+
+	var err error
+	if v := q.Get("news_id"); v != "" {
+		if f.NewsID, err = uuid.Parse(v); err != nil {
+			errs = errors.Join(errs, newFieldError("news_id", FieldCodeInvalid, "is not a valid uuid: %s", v))
+		}
+	}
+	f.Actor = q.Get("actor")
+	if v := q.Get("action"); v != "" {
+		switch a := news.AuditAction(v); a {
+		case news.AuditCreate, news.AuditUpdate, news.AuditDelete, news.AuditRestore, news.AuditPurge, news.AuditTransition:
+			f.Action = a
+		default:
+			errs = errors.Join(errs, newFieldError("action", FieldCodeInvalid,
+				"must be one of create, update, delete, restore, purge, transition: %s", v))
+		}
+	}
+	if f.Since, err = parseTimeParam(q, "since"); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if f.Until, err = parseTimeParam(q, "until"); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Since.Before(f.Until) {
+		errs = errors.Join(errs, newFieldError("since", FieldCodeInvalid, "must be before until"))
+	}
+	f.Limit, f.Offset, err = parseLimitOffset(q)
+	errs = errors.Join(errs, err)
+	return f, errs
+}
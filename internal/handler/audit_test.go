@@ -0,0 +1,163 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/handler"
+	mockshandler "github.com/prashsamosa/newsapi/internal/handler/mocks"
+	"github.com/prashsamosa/newsapi/internal/news"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_GetAudit(t *testing.T) {
+	newsID := uuid.New()
+	testCases := []struct {
+		name           string
+		query          string
+		setup          func(testing.TB) *mockshandler.MockAuditStorer
+		expectedStatus int
+	}{
+		{
+			name:  "unsupported query parameter",
+			query: "?sort=id",
+			setup: func(tb testing.TB) *mockshandler.MockAuditStorer {
+				tb.Helper()
+				return mockshandler.NewMockAuditStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid news id",
+			query: "?news_id=invalid",
+			setup: func(tb testing.TB) *mockshandler.MockAuditStorer {
+				tb.Helper()
+				return mockshandler.NewMockAuditStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid action",
+			query: "?action=read",
+			setup: func(tb testing.TB) *mockshandler.MockAuditStorer {
+				tb.Helper()
+				return mockshandler.NewMockAuditStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "since after until",
+			query: "?since=2024-02-01T00:00:00Z&until=2024-01-01T00:00:00Z",
+			setup: func(tb testing.TB) *mockshandler.MockAuditStorer {
+				tb.Helper()
+				return mockshandler.NewMockAuditStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "db error",
+			setup: func(tb testing.TB) *mockshandler.MockAuditStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockAuditStorer(gomock.NewController(tb))
+				ms.EXPECT().FindAudit(gomock.Any(), gomock.Any()).Return(nil, 0, errors.New("db error"))
+				return ms
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:  "success",
+			query: "?news_id=" + newsID.String() + "&actor=alice&action=update&limit=5&offset=10",
+			setup: func(tb testing.TB) *mockshandler.MockAuditStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockAuditStorer(gomock.NewController(tb))
+				ms.EXPECT().FindAudit(gomock.Any(), news.AuditFilter{
+					NewsID: newsID,
+					Actor:  "alice",
+					Action: news.AuditUpdate,
+					Limit:  5,
+					Offset: 10,
+				}).Return([]*news.AuditEntry{{NewsID: newsID}}, 1, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/audit"+tc.query, http.NoBody)
+
+			// Act
+			handler.GetAudit(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func Test_GetNewsAudit(t *testing.T) {
+	newsID := uuid.New()
+	testCases := []struct {
+		name           string
+		newsID         string
+		query          string
+		setup          func(testing.TB) *mockshandler.MockAuditStorer
+		expectedStatus int
+	}{
+		{
+			name:   "invalid news id",
+			newsID: "invalid",
+			setup: func(tb testing.TB) *mockshandler.MockAuditStorer {
+				tb.Helper()
+				return mockshandler.NewMockAuditStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "news id in the query",
+			newsID: newsID.String(),
+			query:  "?news_id=" + uuid.NewString(),
+			setup: func(tb testing.TB) *mockshandler.MockAuditStorer {
+				tb.Helper()
+				return mockshandler.NewMockAuditStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "success",
+			newsID: newsID.String(),
+			setup: func(tb testing.TB) *mockshandler.MockAuditStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockAuditStorer(gomock.NewController(tb))
+				ms.EXPECT().FindAudit(gomock.Any(), news.AuditFilter{
+					NewsID: newsID,
+					Limit:  handler.DefaultPageLimit,
+				}).Return([]*news.AuditEntry{}, 0, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/news/"+tc.newsID+"/audit"+tc.query, http.NoBody)
+			r.SetPathValue("news_id", tc.newsID)
+
+			// Act
+			handler.GetNewsAudit(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/logger"
+)
+
+// APIKeyHeader is the request header carrying an API key, as an alternative
+// to the Authorization header.
+const APIKeyHeader = "X-API-Key"
+
+var errMissingCredentials = errors.New("the request has no credentials")
+
+// Authenticate rejects the requests without valid credentials, sent as an
+// Authorization bearer token or in the X-API-Key header. The principal of
+// the credentials is added to the request context.
+func Authenticate(a Authenticator, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+
+		credentials, err := requestCredentials(r)
+		if err != nil {
+			log.Error("unauthenticated request", "error", err)
+			writeUnauthenticated(w, r, err)
+			return
+		}
+		p, err := a.Authenticate(ctx, credentials)
+		if err != nil {
+			if !errors.Is(err, auth.ErrInvalidCredentials) {
+				log.Error("failed to authenticate", "error", err)
+				writeProblem(w, r, http.StatusInternalServerError, CodeInternal, err)
+				return
+			}
+			log.Error("unauthenticated request", "error", err)
+			writeUnauthenticated(w, r, err)
+			return
+		}
+
+		ctx = auth.NewContext(ctx, p)
+		ctx = logger.CtxWithLogger(ctx, log.With("subject", p.Subject))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// RequireScope rejects the requests whose principal lacks the scope.
+func RequireScope(scope auth.Scope, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, ok := auth.FromContext(r.Context())
+		if !ok {
+			writeUnauthenticated(w, r, errMissingCredentials)
+			return
+		}
+		if !p.HasScope(scope) {
+			logger.FromContext(r.Context()).Error("missing scope", "scope", scope)
+			writeProblem(w, r, http.StatusForbidden, CodeForbidden, fmt.Errorf("the %s scope is required", scope))
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// requestCredentials returns the bearer token or the API key of the request.
+func requestCredentials(r *http.Request) (string, error) {
+	if h := r.Header.Get("Authorization"); h != "" {
+		scheme, token, ok := strings.Cut(h, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			return "", errors.New("the authorization header must be a bearer token")
+		}
+		return strings.TrimSpace(token), nil
+	}
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key, nil
+	}
+	return "", errMissingCredentials
+}
+
+func writeUnauthenticated(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="newsapi"`)
+	writeProblem(w, r, http.StatusUnauthorized, CodeUnauthenticated, err)
+}
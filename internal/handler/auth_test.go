@@ -0,0 +1,144 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/handler"
+	mockshandler "github.com/prashsamosa/newsapi/internal/handler/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Authenticate(t *testing.T) {
+	testCases := []struct {
+		name            string
+		header          http.Header
+		setup           func(testing.TB) *mockshandler.MockAuthenticator
+		expectedStatus  int
+		expectedSubject string
+	}{
+		{
+			name: "no credentials",
+			setup: func(tb testing.TB) *mockshandler.MockAuthenticator {
+				tb.Helper()
+				return mockshandler.NewMockAuthenticator(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "not a bearer token",
+			header: http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+			setup: func(tb testing.TB) *mockshandler.MockAuthenticator {
+				tb.Helper()
+				return mockshandler.NewMockAuthenticator(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid credentials",
+			header: http.Header{"Authorization": {"Bearer nk_unknown"}},
+			setup: func(tb testing.TB) *mockshandler.MockAuthenticator {
+				tb.Helper()
+				ma := mockshandler.NewMockAuthenticator(gomock.NewController(t))
+				ma.EXPECT().Authenticate(gomock.Any(), "nk_unknown").Return(nil, auth.ErrInvalidCredentials)
+				return ma
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "db error",
+			header: http.Header{"X-Api-Key": {"nk_key"}},
+			setup: func(tb testing.TB) *mockshandler.MockAuthenticator {
+				tb.Helper()
+				ma := mockshandler.NewMockAuthenticator(gomock.NewController(t))
+				ma.EXPECT().Authenticate(gomock.Any(), "nk_key").Return(nil, errors.New("db error"))
+				return ma
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "bearer token",
+			header: http.Header{"Authorization": {"Bearer nk_key"}},
+			setup: func(tb testing.TB) *mockshandler.MockAuthenticator {
+				tb.Helper()
+				ma := mockshandler.NewMockAuthenticator(gomock.NewController(t))
+				ma.EXPECT().Authenticate(gomock.Any(), "nk_key").Return(&auth.Principal{Subject: "reader"}, nil)
+				return ma
+			},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "reader",
+		},
+		{
+			name:   "api key header",
+			header: http.Header{"X-Api-Key": {"nk_key"}},
+			setup: func(tb testing.TB) *mockshandler.MockAuthenticator {
+				tb.Helper()
+				ma := mockshandler.NewMockAuthenticator(gomock.NewController(t))
+				ma.EXPECT().Authenticate(gomock.Any(), "nk_key").Return(&auth.Principal{Subject: "reader"}, nil)
+				return ma
+			},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "reader",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/news", http.NoBody)
+			for k, v := range tc.header {
+				r.Header[k] = v
+			}
+			var subject string
+			next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				p, _ := auth.FromContext(r.Context())
+				subject = p.Subject
+			})
+
+			// Act
+			handler.Authenticate(tc.setup(t), next)(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			assert.Equal(t, tc.expectedSubject, subject)
+			if tc.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Result().Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func Test_RequireScope(t *testing.T) {
+	testCases := []struct {
+		name           string
+		principal      *auth.Principal
+		expectedStatus int
+	}{
+		{name: "unauthenticated", expectedStatus: http.StatusUnauthorized},
+		{name: "missing scope", principal: &auth.Principal{Scopes: []auth.Scope{auth.ScopeNewsRead}}, expectedStatus: http.StatusForbidden},
+		{name: "granted", principal: &auth.Principal{Scopes: []auth.Scope{auth.ScopeNewsWrite}}, expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/news", http.NoBody)
+			if tc.principal != nil {
+				r = r.WithContext(auth.NewContext(r.Context(), tc.principal))
+			}
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			// Act
+			handler.RequireScope(auth.ScopeNewsWrite, next)(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/logger"
+	"github.com/prashsamosa/newsapi/internal/news"
+)
+
+const (
+	// MaxBatchSize is the largest number of operations in a batch.
+	MaxBatchSize = 1000
+	// MaxBatchRequestSize is the largest batch request body accepted.
+	MaxBatchRequestSize = 32 << 20
+)
+
+// BatchNews handler. Every operation is validated and reported on its own
+// in a 207 Multi-Status response. In an atomic batch, a single invalid or
+// failed operation aborts all the others with 424 Failed Dependency.
+func BatchNews(ns NewsStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+
+		var batchRequestBody BatchReqBody
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBatchRequestSize)).Decode(&batchRequestBody); err != nil {
+			log.Error("failed to decode the request", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, err)
+			return
+		}
+		switch n := len(batchRequestBody.Operations); {
+		case n == 0:
+			writeProblem(w, r, http.StatusBadRequest, CodeValidationFailed, newFieldError("operations", FieldCodeRequired, "cannot be empty"))
+			return
+		case n > MaxBatchSize:
+			writeProblem(w, r, http.StatusBadRequest, CodeValidationFailed, newFieldError("operations", FieldCodeTooMany, "cannot have more than %d operations", MaxBatchSize))
+			return
+		}
+
+		results := make([]BatchItemResponse, len(batchRequestBody.Operations))
+		var (
+			ops     []news.BatchOperation
+			idx     []int
+			invalid bool
+		)
+		for i, op := range batchRequestBody.Operations {
+			results[i].Index = i
+			o, err := op.validate()
+			if err != nil {
+				invalid = true
+				results[i].Status = http.StatusBadRequest
+				results[i].Error = newProblem(r, http.StatusBadRequest, CodeValidationFailed, err)
+				continue
+			}
+			ops = append(ops, o)
+			idx = append(idx, i)
+		}
+
+		if invalid && batchRequestBody.Atomic {
+			log.Error("atomic batch has invalid operations")
+			for _, i := range idx {
+				results[i].Status = http.StatusFailedDependency
+				results[i].Error = newProblem(r, http.StatusFailedDependency, news.CodeBatchAborted, news.ErrBatchAborted)
+			}
+			writeBatchResponse(w, r, results)
+			return
+		}
+
+		storeResults, err := ns.Batch(ctx, ops, batchRequestBody.Atomic)
+		if err != nil {
+			log.Error("error running the batch", "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+		for j, res := range storeResults {
+			i := idx[j]
+			if res.Err != nil {
+				status, code := storeErrorStatus(res.Err)
+				results[i].Status = status
+				results[i].Error = newProblem(r, status, code, res.Err)
+				continue
+			}
+			results[i].News = res.Record
+			switch ops[j].Op {
+			case news.BatchCreate:
+				results[i].Status = http.StatusCreated
+			case news.BatchUpdate:
+				results[i].Status = http.StatusOK
+			case news.BatchDelete:
+				results[i].Status = http.StatusNoContent
+			}
+		}
+		writeBatchResponse(w, r, results)
+	}
+}
+
+// validate checks the operation and returns it in its store representation.
+func (op BatchOperationReqBody) validate() (news.BatchOperation, error) {
+	o := news.BatchOperation{Op: op.Op, ID: op.ID, Version: op.Version}
+	switch op.Op {
+	case news.BatchCreate, news.BatchUpdate, news.BatchDelete:
+	default:
+		return o, newFieldError("op", FieldCodeInvalid, "must be one of create, update, delete: %s", op.Op)
+	}
+	if op.Op != news.BatchCreate && op.ID == uuid.Nil {
+		return o, newFieldError("id", FieldCodeRequired, "cannot be empty")
+	}
+	if op.Version < 0 {
+		return o, newFieldError("version", FieldCodeInvalid, "must be a non-negative integer: %d", op.Version)
+	}
+	if op.Op == news.BatchDelete {
+		return o, nil
+	}
+
+	if op.News == nil {
+		return o, newFieldError("news", FieldCodeRequired, "cannot be empty")
+	}
+	if op.Op == news.BatchUpdate && op.News.ID != uuid.Nil && op.News.ID != op.ID {
+		return o, newFieldError("id", FieldCodeInvalid, "does not match the news id")
+	}
+	n, err := op.News.Validate()
+	if err != nil {
+		return o, err
+	}
+	o.Record = n
+	return o, nil
+}
+
+// writeBatchResponse writes the per operation results.
+func writeBatchResponse(w http.ResponseWriter, r *http.Request, results []BatchItemResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusMultiStatus)
+	if err := json.NewEncoder(w).Encode(BatchResponse{Results: results}); err != nil {
+		logger.FromContext(r.Context()).Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,153 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prashsamosa/newsapi/internal/handler"
+	mockshandler "github.com/prashsamosa/newsapi/internal/handler/mocks"
+	"github.com/prashsamosa/newsapi/internal/news"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const batchNews = `{
+	"author": "code learn",
+	"content": "news content",
+	"title": "first news",
+	"summary": "first news post",
+	"created_at": "2024-04-07T05:13:27+00:00",
+	"source": "https://example.com",
+	"tags": ["politics"]
+}`
+
+func Test_BatchNews(t *testing.T) {
+	testCases := []struct {
+		name             string
+		body             string
+		setup            func(testing.TB) *mockshandler.MockNewsStorer
+		expectedStatus   int
+		expectedStatuses []int
+	}{
+		{
+			name: "invalid request body json",
+			body: `{`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "no operations",
+			body: `{"operations": []}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "db error",
+			body: `{"operations": [{"op": "create", "news": ` + batchNews + `}]}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().Batch(gomock.Any(), gomock.Any(), false).Return(nil, errors.New("db error"))
+				return ms
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "per item",
+			body: `{"operations": [
+				{"op": "create", "news": ` + batchNews + `},
+				{"op": "create", "news": {"title": "invalid"}},
+				{"op": "update", "id": "3b082d9d-1dc7-4d1f-907e-50d449a03d45", "version": 2, "news": ` + batchNews + `},
+				{"op": "delete", "id": "3b082d9d-1dc7-4d1f-907e-50d449a03d45"},
+				{"op": "delete"},
+				{"op": "upsert"}
+			]}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().Batch(gomock.Any(), gomock.Len(3), false).Return([]news.BatchResult{
+					{Record: &news.Record{}},
+					{Err: news.NewCustomError(news.ErrVersionMismatch, http.StatusPreconditionFailed)},
+					{},
+				}, nil)
+				return ms
+			},
+			expectedStatus: http.StatusMultiStatus,
+			expectedStatuses: []int{
+				http.StatusCreated,
+				http.StatusBadRequest,
+				http.StatusPreconditionFailed,
+				http.StatusNoContent,
+				http.StatusBadRequest,
+				http.StatusBadRequest,
+			},
+		},
+		{
+			name: "atomic with an invalid operation",
+			body: `{"atomic": true, "operations": [
+				{"op": "create", "news": ` + batchNews + `},
+				{"op": "update", "news": ` + batchNews + `}
+			]}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus:   http.StatusMultiStatus,
+			expectedStatuses: []int{http.StatusFailedDependency, http.StatusBadRequest},
+		},
+		{
+			name: "atomic",
+			body: `{"atomic": true, "operations": [
+				{"op": "create", "news": ` + batchNews + `},
+				{"op": "delete", "id": "3b082d9d-1dc7-4d1f-907e-50d449a03d45"}
+			]}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().Batch(gomock.Any(), gomock.Len(2), true).Return([]news.BatchResult{
+					{Err: news.NewCustomError(news.ErrBatchAborted, http.StatusFailedDependency)},
+					{Err: news.NewCustomError(errors.New("not found"), http.StatusNotFound)},
+				}, nil)
+				return ms
+			},
+			expectedStatus:   http.StatusMultiStatus,
+			expectedStatuses: []int{http.StatusFailedDependency, http.StatusNotFound},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/news:batch", strings.NewReader(tc.body))
+
+			// Act
+			handler.BatchNews(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			if tc.expectedStatuses == nil {
+				return
+			}
+			var resp handler.BatchResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			require.Len(t, resp.Results, len(tc.expectedStatuses))
+			for i, res := range resp.Results {
+				assert.Equal(t, i, res.Index)
+				assert.Equal(t, tc.expectedStatuses[i], res.Status, "operation %d", i)
+				assert.Equal(t, res.Status >= http.StatusBadRequest, res.Error != nil, "operation %d", i)
+			}
+		})
+	}
+}
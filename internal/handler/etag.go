@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// errMultipleETags is returned when a precondition lists several entity tags.
+var errMultipleETags = errors.New("multiple entity tags are not supported")
+
+// ETag returns the entity tag of a news version.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parseIfMatch returns the news version required by an If-Match header.
+// It returns 0 when the header is empty or "*", which match any version.
+func parseIfMatch(h string) (int, error) {
+	h = strings.TrimSpace(h)
+	if h == "" || h == "*" {
+		return 0, nil
+	}
+	if strings.Contains(h, ",") {
+		return 0, errMultipleETags
+	}
+	// If-Match uses the strong comparison, a weak tag never matches.
+	version, err := parseETag(h)
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// matchesIfNoneMatch reports whether an If-None-Match header matches the
+// news version using the weak comparison.
+func matchesIfNoneMatch(h string, version int) bool {
+	h = strings.TrimSpace(h)
+	if h == "" {
+		return false
+	}
+	if h == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(h, ",") {
+		v, err := parseETag(strings.TrimPrefix(strings.TrimSpace(tag), "W/"))
+		if err == nil && v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// parseETag parses a strong entity tag produced by ETag.
+func parseETag(tag string) (int, error) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, fmt.Errorf("invalid entity tag: %s", tag)
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid entity tag: %s", tag)
+	}
+	return version, nil
+}
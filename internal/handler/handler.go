@@ -3,9 +3,14 @@ package handler
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/idempotency"
 	"github.com/prashsamosa/newsapi/internal/logger"
 	"github.com/prashsamosa/newsapi/internal/news"
 	"github.com/google/uuid"
@@ -19,15 +24,73 @@ type NewsStorer interface {
 	Create(context.Context, *news.Record) (*news.Record, error)
 	// FindByID news by its ID.
 	FindByID(context.Context, uuid.UUID) (*news.Record, error)
-	// FindAll returns all news in the store.
-	FindAll(context.Context) ([]*news.Record, error)
+	// FindPage returns a single page of news from the store.
+	FindPage(context.Context, news.ListParams) (*news.Page, error)
+	// Search runs a full-text search over the news.
+	Search(context.Context, news.SearchParams) ([]*news.SearchResult, error)
 	// DeleteByID deletes a news item by its ID.
-	DeleteByID(context.Context, uuid.UUID) error
+	DeleteByID(context.Context, uuid.UUID, news.DeleteOptions) error
 	// UpdateByID updates a news resource by its ID.
 	UpdateByID(context.Context, uuid.UUID, *news.Record) error
+	// Batch runs several create, update and delete operations.
+	Batch(ctx context.Context, ops []news.BatchOperation, atomic bool) ([]news.BatchResult, error)
 }
 
-// PostNews handler.
+// TrashStorer represents the store operations on soft deleted news.
+type TrashStorer interface {
+	// FindTrash returns a page of soft deleted news.
+	FindTrash(ctx context.Context, limit, offset int) (*news.Page, error)
+	// RestoreByID moves a news out of the trash.
+	RestoreByID(context.Context, uuid.UUID) (*news.Record, error)
+	// PurgeByID permanently deletes a news.
+	PurgeByID(context.Context, uuid.UUID) error
+}
+
+// StatusStorer represents the editorial workflow store operations.
+type StatusStorer interface {
+	// Transition moves a news to another editorial state.
+	Transition(ctx context.Context, id uuid.UUID, to news.Status, version int) (*news.Record, error)
+}
+
+// AuditStorer represents the audit log store operations.
+type AuditStorer interface {
+	// FindAudit returns the audit log entries matching the filter and
+	// their number.
+	FindAudit(context.Context, news.AuditFilter) ([]*news.AuditEntry, int, error)
+}
+
+// RevisionStorer represents the news revisions store operations.
+type RevisionStorer interface {
+	// FindRevisions returns a page of the revisions of a news and their
+	// number.
+	FindRevisions(ctx context.Context, id uuid.UUID, limit, offset int) ([]*news.Revision, int, error)
+	// FindRevision returns a single revision of a news.
+	FindRevision(ctx context.Context, id uuid.UUID, revision int) (*news.Revision, error)
+	// DiffRevisions returns the fields changed between two revisions.
+	DiffRevisions(ctx context.Context, id uuid.UUID, from, to int) (map[string]news.Change, error)
+	// RestoreRevision updates a news with the content of a revision.
+	RestoreRevision(ctx context.Context, id uuid.UUID, revision, version int) (*news.Record, error)
+}
+
+// IdempotencyStorer represents the idempotency key store operations.
+type IdempotencyStorer interface {
+	// Reserve claims the key for a request, or returns the record of the
+	// request that already holds it.
+	Reserve(ctx context.Context, key, fingerprint string) (*idempotency.Record, bool, error)
+	// Complete stores the response of the request holding the key.
+	Complete(context.Context, *idempotency.Record) error
+	// Release frees the key of a request that did not complete.
+	Release(context.Context, *idempotency.Record) error
+}
+
+// Authenticator represents the validation of the request credentials.
+type Authenticator interface {
+	// Authenticate returns the principal of the credentials.
+	Authenticate(ctx context.Context, credentials string) (*auth.Principal, error)
+}
+
+// PostNews handler. The created news is returned unless the client prefers
+// a minimal response.
 func PostNews(ns NewsStorer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -37,53 +100,86 @@ func PostNews(ns NewsStorer) http.HandlerFunc {
 		var newsRequestBody NewsPostReqBody
 		if err := json.NewDecoder(r.Body).Decode(&newsRequestBody); err != nil {
 			log.Error("failed to decode the request", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, err)
 			return
 		}
 
 		n, err := newsRequestBody.Validate()
 		if err != nil {
 			log.Error("request validation failed", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			if _, wrErr := w.Write([]byte(err.Error())); wrErr != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-			}
+			writeProblem(w, r, http.StatusBadRequest, CodeValidationFailed, err)
 			return
 		}
 
-		if _, err := ns.Create(ctx, n); err != nil {
+		created, err := ns.Create(ctx, n)
+		if err != nil {
 			log.Error("error creating news", "error", err)
-			var dbErr *news.CustomError
-			if errors.As(err, &dbErr) {
-				w.WriteHeader(dbErr.HTTPStatusCode())
-				return
-			}
-			w.WriteHeader(http.StatusInternalServerError)
+			writeStoreError(w, r, err)
 			return
 		}
+
+		w.Header().Set("Location", "/news/"+created.ID.String())
+		w.Header().Set("ETag", ETag(created.Version))
+		if preferReturnMinimal(r.Header.Values("Prefer")) {
+			w.Header().Set("Preference-Applied", "return=minimal")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(created); err != nil {
+			log.Error("failed to write response", "error", err)
+			return
+		}
 	}
 }
 
+// preferReturnMinimal reports whether the Prefer headers (RFC 7240) ask for
+// an empty response body.
+func preferReturnMinimal(prefer []string) bool {
+	for _, h := range prefer {
+		for pref := range strings.SplitSeq(h, ",") {
+			name, _, _ := strings.Cut(pref, ";")
+			if strings.EqualFold(strings.ReplaceAll(strings.TrimSpace(name), " ", ""), "return=minimal") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // GetAllNews handler.
 func GetAllNews(ns NewsStorer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		log := logger.FromContext(ctx)
 		log.Info("request received")
-		n, err := ns.FindAll(ctx)
+
+		params, err := parseListParams(r.URL.Query())
+		if err != nil {
+			log.Error("invalid query parameters", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidQuery, err)
+			return
+		}
+		params.Filter.Statuses, err = visibleStatuses(ctx, params.Filter.Statuses)
+		if err != nil {
+			log.Error("unpublished news requested", "error", err)
+			writeProblem(w, r, http.StatusForbidden, CodeForbidden, err)
+			return
+		}
+		if !canSeeUnpublished(ctx) {
+			params.Filter.LiveAt = time.Now()
+		}
+
+		page, err := ns.FindPage(ctx, params)
 		if err != nil {
 			log.Error("failed to fetch all news", "error", err)
-			var dbErr *news.CustomError
-			if errors.As(err, &dbErr) {
-				w.WriteHeader(dbErr.HTTPStatusCode())
-				return
-			}
-			w.WriteHeader(http.StatusInternalServerError)
+			writeStoreError(w, r, err)
 			return
 		}
 
-		allNewsResponse := AllNewsResponse{News: n}
+		allNewsResponse := NewAllNewsResponse(page)
 		if err := json.NewEncoder(w).Encode(allNewsResponse); err != nil {
 			log.Error("failed to write response", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
@@ -92,6 +188,44 @@ func GetAllNews(ns NewsStorer) http.HandlerFunc {
 	}
 }
 
+// SearchNews handler.
+func SearchNews(ns NewsStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+
+		params, err := parseSearchParams(r.URL.Query())
+		if err != nil {
+			log.Error("invalid query parameters", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidQuery, err)
+			return
+		}
+		params.Statuses, err = visibleStatuses(ctx, params.Statuses)
+		if err != nil {
+			log.Error("unpublished news requested", "error", err)
+			writeProblem(w, r, http.StatusForbidden, CodeForbidden, err)
+			return
+		}
+		if !canSeeUnpublished(ctx) {
+			params.LiveAt = time.Now()
+		}
+
+		results, err := ns.Search(ctx, params)
+		if err != nil {
+			log.Error("failed to search news", "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(SearchNewsResponse{Results: results}); err != nil {
+			log.Error("failed to write response", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 // GetNewsByID handler.
 func GetNewsByID(ns NewsStorer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -102,18 +236,24 @@ func GetNewsByID(ns NewsStorer) http.HandlerFunc {
 		newsUUID, err := uuid.Parse(newsID)
 		if err != nil {
 			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
-			w.WriteHeader(http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
 			return
 		}
 		n, err := ns.FindByID(ctx, newsUUID)
 		if err != nil {
 			log.Error("news not found", "newsId", newsID)
-			var dbErr *news.CustomError
-			if errors.As(err, &dbErr) {
-				w.WriteHeader(dbErr.HTTPStatusCode())
-				return
-			}
-			w.WriteHeader(http.StatusInternalServerError)
+			writeStoreError(w, r, err)
+			return
+		}
+		if !n.Live(time.Now()) && !canSeeUnpublished(ctx) {
+			log.Error("news not published", "newsId", newsID, "status", n.Status)
+			writeProblem(w, r, http.StatusNotFound, news.CodeNotFound, errNewsNotFound)
+			return
+		}
+
+		w.Header().Set("ETag", ETag(n.Version))
+		if matchesIfNoneMatch(r.Header.Get("If-None-Match"), n.Version) {
+			w.WriteHeader(http.StatusNotModified)
 			return
 		}
 
@@ -131,38 +271,128 @@ func UpdateNewsByID(ns NewsStorer) http.HandlerFunc {
 		ctx := r.Context()
 		log := logger.FromContext(ctx)
 		log.Info("request received")
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
+		if err != nil {
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
+			return
+		}
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			log.Error("invalid if-match header", "error", err)
+			writeProblem(w, r, http.StatusPreconditionFailed, CodePreconditionFailed, err)
+			return
+		}
 
 		var newsRequestBody NewsPostReqBody
 		if err := json.NewDecoder(r.Body).Decode(&newsRequestBody); err != nil {
 			log.Error("failed to decode the request", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, err)
 			return
 		}
 
-		n, err := newsRequestBody.Validate()
+		updateNews(w, r, ns, newsUUID, version, newsRequestBody)
+	}
+}
+
+// PatchNewsByID handler. The request body is either a JSON Merge Patch
+// (RFC 7396) or a JSON Patch (RFC 6902) document, selected by Content-Type.
+func PatchNewsByID(ns NewsStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
 		if err != nil {
-			log.Error("request validation failed", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			if _, wrErr := w.Write([]byte(err.Error())); wrErr != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-			}
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
 			return
 		}
 
-		if err := ns.UpdateByID(ctx, n.ID, n); err != nil {
-			log.Error("error updating news", "error", err)
-			var dbErr *news.CustomError
-			if errors.As(err, &dbErr) {
-				w.WriteHeader(dbErr.HTTPStatusCode())
-				return
-			}
-			w.WriteHeader(http.StatusInternalServerError)
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			log.Error("invalid if-match header", "error", err)
+			writeProblem(w, r, http.StatusPreconditionFailed, CodePreconditionFailed, err)
+			return
+		}
+
+		patchType, err := parsePatchType(r.Header.Get("Content-Type"))
+		if err != nil {
+			log.Error("unsupported patch type", "error", err)
+			w.Header().Set("Accept-Patch", MergePatchContentType+", "+JSONPatchContentType)
+			writeProblem(w, r, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, err)
 			return
 		}
+
+		patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxPatchSize))
+		if err != nil {
+			log.Error("failed to read the request", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, err)
+			return
+		}
+
+		n, err := ns.FindByID(ctx, newsUUID)
+		if err != nil {
+			log.Error("news not found", "newsId", newsID)
+			writeStoreError(w, r, err)
+			return
+		}
+
+		if version > 0 && version != n.Version {
+			log.Error("news version mismatch", "newsId", newsID, "version", n.Version, "ifMatch", version)
+			writeProblem(w, r, http.StatusPreconditionFailed, news.CodeVersionMismatch, news.ErrVersionMismatch)
+			return
+		}
+
+		newsRequestBody, err := applyPatch(patchType, NewNewsPostReqBody(n), patch)
+		if err != nil {
+			log.Error("failed to apply the patch", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, err)
+			return
+		}
+
+		// The patch was applied to the fetched version, so the update must
+		// not overwrite a concurrent change.
+		updateNews(w, r, ns, newsUUID, n.Version, newsRequestBody)
 	}
 }
 
-// DeleteNewsByID handler.
+// updateNews validates the request body and updates the news with the id.
+// The id from the path is authoritative, a body with another id is rejected.
+// A non zero version makes the update conditional on the stored version.
+func updateNews(w http.ResponseWriter, r *http.Request, ns NewsStorer, id uuid.UUID, version int, newsRequestBody NewsPostReqBody) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	if newsRequestBody.ID != uuid.Nil && newsRequestBody.ID != id {
+		log.Error("news id mismatch", "newsId", id, "bodyId", newsRequestBody.ID)
+		writeProblem(w, r, http.StatusBadRequest, CodeValidationFailed, newFieldError("id", FieldCodeInvalid, "does not match the news id in path"))
+		return
+	}
+	newsRequestBody.ID = id
+
+	n, err := newsRequestBody.Validate()
+	if err != nil {
+		log.Error("request validation failed", "error", err)
+		writeProblem(w, r, http.StatusBadRequest, CodeValidationFailed, err)
+		return
+	}
+
+	n.Version = version
+	if err := ns.UpdateByID(ctx, id, n); err != nil {
+		log.Error("error updating news", "error", err)
+		writeStoreError(w, r, err)
+		return
+	}
+	if n.Version > 0 {
+		w.Header().Set("ETag", ETag(n.Version))
+	}
+}
+
+// DeleteNewsByID handler. Deleting an unknown or already deleted news is a
+// not found error, unless the idempotent query parameter is true.
 func DeleteNewsByID(ns NewsStorer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -171,18 +401,30 @@ func DeleteNewsByID(ns NewsStorer) http.HandlerFunc {
 		newsUUID, err := uuid.Parse(newsID)
 		if err != nil {
 			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
-			w.WriteHeader(http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
 			return
 		}
 
-		if err := ns.DeleteByID(ctx, newsUUID); err != nil {
-			log.Error("news not found", "newsId", newsID, "error", err)
-			var dbErr *news.CustomError
-			if errors.As(err, &dbErr) {
-				w.WriteHeader(dbErr.HTTPStatusCode())
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			log.Error("invalid if-match header", "error", err)
+			writeProblem(w, r, http.StatusPreconditionFailed, CodePreconditionFailed, err)
+			return
+		}
+
+		opts := news.DeleteOptions{Version: version}
+		if v := r.URL.Query().Get("idempotent"); v != "" {
+			opts.Idempotent, err = strconv.ParseBool(v)
+			if err != nil {
+				log.Error("invalid idempotent parameter", "idempotent", v, "error", err)
+				writeProblem(w, r, http.StatusBadRequest, CodeInvalidQuery, newFieldError("idempotent", FieldCodeInvalid, "must be a boolean: %s", v))
 				return
 			}
-			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		if err := ns.DeleteByID(ctx, newsUUID, opts); err != nil {
+			log.Error("news not found", "newsId", newsID, "error", err)
+			writeStoreError(w, r, err)
 			return
 		}
 		w.WriteHeader(http.StatusNoContent)
@@ -1,12 +1,15 @@
 package handler_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/prashsamosa/newsapi/internal/handler"
 	mockshandler "github.com/prashsamosa/newsapi/internal/handler/mocks"
@@ -20,8 +23,10 @@ func Test_PostNews(t *testing.T) {
 	testCases := []struct {
 		name           string
 		body           io.Reader
+		prefer         string
 		setup          func(tb testing.TB) *mockshandler.MockNewsStorer
 		expectedStatus int
+		expectedID     string
 	}{
 		{
 			name: "invalid request body json",
@@ -108,7 +113,33 @@ func Test_PostNews(t *testing.T) {
 			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
 				tb.Helper()
 				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
-				ms.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ms.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n *news.Record) (*news.Record, error) {
+					n.ID = uuid.MustParse("3b082d9d-1dc7-4d1f-907e-50d449a03d45")
+					n.Version = 1
+					return n, nil
+				})
+				return ms
+			},
+			expectedStatus: http.StatusCreated,
+			expectedID:     "3b082d9d-1dc7-4d1f-907e-50d449a03d45",
+		},
+		{
+			name:   "success with minimal return",
+			prefer: "handling=lenient, return=minimal",
+			body: strings.NewReader(`
+			{
+			"author": "code learn",
+			"content": "news content",
+			"title": "first news",
+			"summary": "first news post",
+			"created_at": "2024-04-07T05:13:27+00:00",
+			"source": "https://example.com",
+			"tags": ["politics"]
+			}`),
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&news.Record{ID: uuid.MustParse("3b082d9d-1dc7-4d1f-907e-50d449a03d45")}, nil)
 				return ms
 			},
 			expectedStatus: http.StatusCreated,
@@ -120,12 +151,27 @@ func Test_PostNews(t *testing.T) {
 			// Arrange
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodPost, "/", tc.body)
+			if tc.prefer != "" {
+				r.Header.Set("Prefer", tc.prefer)
+			}
 
 			// Act
 			handler.PostNews(tc.setup(t))(w, r)
 
 			// Assert
 			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			if tc.expectedStatus != http.StatusCreated {
+				return
+			}
+			assert.Equal(t, "/news/3b082d9d-1dc7-4d1f-907e-50d449a03d45", w.Result().Header.Get("Location"))
+			if tc.expectedID == "" {
+				assert.Empty(t, w.Body.String())
+				return
+			}
+			var got news.Record
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+			assert.Equal(t, tc.expectedID, got.ID.String())
+			assert.Equal(t, "first news", got.Title)
 		})
 	}
 }
@@ -133,15 +179,106 @@ func Test_PostNews(t *testing.T) {
 func Test_GetAllNews(t *testing.T) {
 	testCases := []struct {
 		name           string
+		query          string
 		setup          func(tb testing.TB) *mockshandler.MockNewsStorer
 		expectedStatus int
 	}{
+		{
+			name:  "invalid limit",
+			query: "?limit=0",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "limit above max",
+			query: "?limit=1000",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid cursor",
+			query: "?cursor=not-a-cursor",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "cursor with offset",
+			query: "?offset=10&cursor=" + news.Cursor{CreatedAt: time.Now(), ID: uuid.New()}.Encode(),
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid sort column",
+			query: "?sort=-summary",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "cursor with custom sort",
+			query: "?sort=title&cursor=" + news.Cursor{CreatedAt: time.Now(), ID: uuid.New()}.Encode(),
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "unknown query parameter",
+			query: "?colour=red",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "malformed created_after",
+			query: "?created_after=yesterday",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid tag_match",
+			query: "?tag=politics&tag_match=some",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "created_after not before created_before",
+			query: "?created_after=2024-04-07T05:13:27Z&created_before=2024-04-06T05:13:27Z",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "db error",
 			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
 				tb.Helper()
 				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
-				ms.EXPECT().FindAll(gomock.Any()).Return(nil, errors.New("db error"))
+				ms.EXPECT().FindPage(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
 				return ms
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -151,7 +288,7 @@ func Test_GetAllNews(t *testing.T) {
 			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
 				tb.Helper()
 				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
-				ms.EXPECT().FindAll(gomock.Any()).Return(nil, news.NewCustomError(errors.New("some error"), http.StatusBadRequest))
+				ms.EXPECT().FindPage(gomock.Any(), gomock.Any()).Return(nil, news.NewCustomError(errors.New("some error"), http.StatusBadRequest))
 				return ms
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -161,7 +298,56 @@ func Test_GetAllNews(t *testing.T) {
 			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
 				tb.Helper()
 				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
-				ms.EXPECT().FindAll(gomock.Any()).Return(nil, nil)
+				ms.EXPECT().FindPage(gomock.Any(), news.ListParams{Limit: handler.DefaultPageLimit}).Return(nil, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "success with limit and offset",
+			query: "?limit=5&offset=10",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindPage(gomock.Any(), news.ListParams{Limit: 5, Offset: 10}).Return(&news.Page{}, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "success with sort",
+			query: "?sort=-updated_at,title",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindPage(gomock.Any(), news.ListParams{
+					Limit: handler.DefaultPageLimit,
+					Sort: []news.SortField{
+						{Column: "updated_at", Desc: true},
+						{Column: "title"},
+						{Column: "id", Desc: true},
+					},
+				}).Return(&news.Page{}, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "success with filters",
+			query: "?author=Batman&tag=tag1&tag=tag2&tag_match=all&source_host=example.com&created_after=2024-04-07T05:13:27Z",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindPage(gomock.Any(), news.ListParams{
+					Limit: handler.DefaultPageLimit,
+					Filter: news.Filter{
+						Author:       "Batman",
+						Tags:         []string{"tag1", "tag2"},
+						TagMatch:     news.TagMatchAll,
+						SourceHost:   "example.com",
+						CreatedAfter: time.Date(2024, 4, 7, 5, 13, 27, 0, time.UTC),
+					},
+				}).Return(&news.Page{}, nil)
 				return ms
 			},
 			expectedStatus: http.StatusOK,
@@ -172,7 +358,7 @@ func Test_GetAllNews(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Arrange
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+			r := httptest.NewRequest(http.MethodGet, "/"+tc.query, http.NoBody)
 
 			// Act
 			handler.GetAllNews(tc.setup(t))(w, r)
@@ -183,12 +369,87 @@ func Test_GetAllNews(t *testing.T) {
 	}
 }
 
+func Test_SearchNews(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		setup          func(tb testing.TB) *mockshandler.MockNewsStorer
+		expectedStatus int
+	}{
+		{
+			name:  "missing query",
+			query: "",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "query too long",
+			query: "?q=" + strings.Repeat("a", handler.MaxSearchQueryLength+1),
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "unknown query parameter",
+			query: "?q=news&author=Batman",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "db error",
+			query: "?q=news",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+				return ms
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:  "success",
+			query: "?q=breaking+news&limit=5",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().Search(gomock.Any(), news.SearchParams{Query: "breaking news", Limit: 5}).Return(nil, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/"+tc.query, http.NoBody)
+
+			// Act
+			handler.SearchNews(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
 func Test_GetNewsByID(t *testing.T) {
 	testCases := []struct {
 		name           string
 		setup          func(tb testing.TB) *mockshandler.MockNewsStorer
 		newsID         string
+		header         http.Header
 		expectedStatus int
+		expectedETag   string
 	}{
 		{
 			name: "invalid news id",
@@ -226,11 +487,38 @@ func Test_GetNewsByID(t *testing.T) {
 			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
 				tb.Helper()
 				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
-				ms.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(nil, nil)
+				ms.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(&news.Record{Version: 3}, nil)
+				return ms
+			},
+			newsID:         uuid.NewString(),
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		{
+			name: "if-none-match stale",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(&news.Record{Version: 3}, nil)
 				return ms
 			},
 			newsID:         uuid.NewString(),
+			header:         http.Header{"If-None-Match": {`"2"`}},
 			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		{
+			name: "not modified",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(&news.Record{Version: 3}, nil)
+				return ms
+			},
+			newsID:         uuid.NewString(),
+			header:         http.Header{"If-None-Match": {`"2", W/"3"`}},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   `"3"`,
 		},
 	}
 
@@ -240,12 +528,16 @@ func Test_GetNewsByID(t *testing.T) {
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
 			r.SetPathValue("news_id", tc.newsID)
+			for k, v := range tc.header {
+				r.Header[k] = v
+			}
 
 			// Act
 			handler.GetNewsByID(tc.setup(t))(w, r)
 
 			// Assert
 			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			assert.Equal(t, tc.expectedETag, w.Result().Header.Get("ETag"))
 		})
 	}
 }
@@ -254,9 +546,97 @@ func Test_UpdateNewsByID(t *testing.T) {
 	testCases := []struct {
 		name           string
 		body           io.Reader
+		newsID         string
+		header         http.Header
 		setup          func(tb testing.TB) *mockshandler.MockNewsStorer
 		expectedStatus int
 	}{
+		{
+			name:   "invalid news id",
+			body:   strings.NewReader(`{}`),
+			newsID: "invalid-uuid",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "body id does not match path id",
+			body: strings.NewReader(`
+			{
+			"id" : "3b082d9d-1dc7-4d1f-907e-50d449a03d45",
+			"author": "code learn",
+			"content": "news content",
+			"title": "first news",
+			"summary": "first news post",
+			"created_at": "2024-04-07T05:13:27+00:00",
+			"source": "https://example.com",
+			"tags": ["politics"]
+			}`),
+			newsID: "6a3483c7-e28e-442e-b603-b06ff60eeeb4",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "body without id uses path id",
+			body: strings.NewReader(`
+			{
+			"author": "code learn",
+			"content": "news content",
+			"title": "first news",
+			"summary": "first news post",
+			"created_at": "2024-04-07T05:13:27+00:00",
+			"source": "https://example.com",
+			"tags": ["politics"]
+			}`),
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				id := uuid.MustParse("3b082d9d-1dc7-4d1f-907e-50d449a03d45")
+				ms.EXPECT().UpdateByID(gomock.Any(), id, gomock.Any()).Return(nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "malformed if-match",
+			body:   strings.NewReader(`{}`),
+			header: http.Header{"If-Match": {`W/"1"`}},
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "stale if-match",
+			body: strings.NewReader(`
+			{
+			"author": "code learn",
+			"content": "news content",
+			"title": "first news",
+			"summary": "first news post",
+			"created_at": "2024-04-07T05:13:27+00:00",
+			"source": "https://example.com",
+			"tags": ["politics"]
+			}`),
+			header: http.Header{"If-Match": {`"2"`}},
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().UpdateByID(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ uuid.UUID, n *news.Record) error {
+						assert.Equal(t, 2, n.Version)
+						return news.NewCustomError(news.ErrVersionMismatch, http.StatusPreconditionFailed)
+					})
+				return ms
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
 		{
 			name: "invalid request json body",
 			body: strings.NewReader(`{`),
@@ -352,7 +732,15 @@ func Test_UpdateNewsByID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Arrange
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodPost, "/", tc.body)
+			r := httptest.NewRequest(http.MethodPut, "/", tc.body)
+			newsID := tc.newsID
+			if newsID == "" {
+				newsID = "3b082d9d-1dc7-4d1f-907e-50d449a03d45"
+			}
+			r.SetPathValue("news_id", newsID)
+			for k, v := range tc.header {
+				r.Header[k] = v
+			}
 
 			// Act
 			handler.UpdateNewsByID(tc.setup(t))(w, r)
@@ -363,11 +751,176 @@ func Test_UpdateNewsByID(t *testing.T) {
 	}
 }
 
+func Test_PatchNewsByID(t *testing.T) {
+	newsID := uuid.MustParse("3b082d9d-1dc7-4d1f-907e-50d449a03d45")
+	existing := func() *news.Record {
+		return &news.Record{
+			ID:        newsID,
+			Author:    "code learn",
+			Title:     "first news",
+			Summary:   "first news post",
+			Content:   "news content",
+			Source:    "https://example.com",
+			Tags:      []string{"politics"},
+			CreatedAt: time.Date(2024, 4, 7, 5, 13, 27, 0, time.UTC),
+			Version:   3,
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		contentType    string
+		ifMatch        string
+		body           string
+		setup          func(tb testing.TB) *mockshandler.MockNewsStorer
+		expectedStatus int
+	}{
+		{
+			name:        "unsupported content type",
+			contentType: "application/json",
+			body:        `{"title": "new title"}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "stale if-match",
+			contentType: handler.MergePatchContentType,
+			ifMatch:     `"2"`,
+			body:        `{"title": "new title"}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), newsID).Return(existing(), nil)
+				return ms
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:        "news not found",
+			contentType: handler.MergePatchContentType,
+			body:        `{"title": "new title"}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), newsID).Return(nil, news.NewCustomError(errors.New("no rows"), http.StatusNotFound))
+				return ms
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "invalid json patch",
+			contentType: handler.JSONPatchContentType,
+			body:        `[{"op": "replace", "path": "/missing/field", "value": "x"}]`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), newsID).Return(existing(), nil)
+				return ms
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "patch changes id",
+			contentType: handler.MergePatchContentType,
+			body:        `{"id": "6a3483c7-e28e-442e-b603-b06ff60eeeb4"}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), newsID).Return(existing(), nil)
+				return ms
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "patched news invalid",
+			contentType: handler.MergePatchContentType,
+			body:        `{"title": null}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), newsID).Return(existing(), nil)
+				return ms
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "merge patch",
+			contentType: handler.MergePatchContentType,
+			body:        `{"title": "new title"}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), newsID).Return(existing(), nil)
+				expected := existing()
+				expected.Title = "new title"
+				ms.EXPECT().UpdateByID(gomock.Any(), newsID, expected).Return(nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "merge patch with if-match",
+			contentType: handler.MergePatchContentType,
+			ifMatch:     `"3"`,
+			body:        `{"summary": "new summary"}`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), newsID).Return(existing(), nil)
+				expected := existing()
+				expected.Summary = "new summary"
+				ms.EXPECT().UpdateByID(gomock.Any(), newsID, expected).Return(nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "json patch",
+			contentType: handler.JSONPatchContentType + "; charset=utf-8",
+			body:        `[{"op": "add", "path": "/tags/-", "value": "elections"}]`,
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().FindByID(gomock.Any(), newsID).Return(existing(), nil)
+				expected := existing()
+				expected.Tags = []string{"politics", "elections"}
+				ms.EXPECT().UpdateByID(gomock.Any(), newsID, expected).Return(nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", tc.contentType)
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+			r.SetPathValue("news_id", newsID.String())
+
+			// Act
+			handler.PatchNewsByID(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
 func Test_DeleteNewsByID(t *testing.T) {
 	testCases := []struct {
 		name           string
 		setup          func(testing.TB) *mockshandler.MockNewsStorer
 		newsID         string
+		ifMatch        string
+		query          string
 		expectedStatus int
 	}{
 		{
@@ -384,7 +937,7 @@ func Test_DeleteNewsByID(t *testing.T) {
 			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
 				tb.Helper()
 				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
-				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db error"))
 				return ms
 			},
 			newsID:         uuid.NewString(),
@@ -395,7 +948,7 @@ func Test_DeleteNewsByID(t *testing.T) {
 			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
 				tb.Helper()
 				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
-				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any()).Return(news.NewCustomError(errors.New("some error"), http.StatusBadRequest))
+				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(news.NewCustomError(errors.New("some error"), http.StatusBadRequest))
 				return ms
 			},
 			newsID:         uuid.NewString(),
@@ -406,20 +959,80 @@ func Test_DeleteNewsByID(t *testing.T) {
 			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
 				tb.Helper()
 				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
-				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any()).Return(nil)
+				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				return ms
+			},
+			newsID:         uuid.NewString(),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any(), news.DeleteOptions{}).
+					Return(news.NewCustomError(errors.New("not found"), http.StatusNotFound))
+				return ms
+			},
+			newsID:         uuid.NewString(),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "idempotent",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any(), news.DeleteOptions{Idempotent: true}).Return(nil)
 				return ms
 			},
 			newsID:         uuid.NewString(),
+			query:          "?idempotent=true",
 			expectedStatus: http.StatusNoContent,
 		},
+		{
+			name: "invalid idempotent",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			newsID:         uuid.NewString(),
+			query:          "?idempotent=sure",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "malformed if-match",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				return mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			},
+			newsID:         uuid.NewString(),
+			ifMatch:        `"1", "2"`,
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "stale if-match",
+			setup: func(tb testing.TB) *mockshandler.MockNewsStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any(), news.DeleteOptions{Version: 2}).
+					Return(news.NewCustomError(news.ErrVersionMismatch, http.StatusPreconditionFailed))
+				return ms
+			},
+			newsID:         uuid.NewString(),
+			ifMatch:        `"2"`,
+			expectedStatus: http.StatusPreconditionFailed,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Arrange
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+			r := httptest.NewRequest(http.MethodDelete, "/"+tc.query, http.NoBody)
 			r.SetPathValue("news_id", tc.newsID)
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
 
 			// Act
 			handler.DeleteNewsByID(tc.setup(t))(w, r)
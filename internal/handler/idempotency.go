@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/logger"
+	"github.com/prashsamosa/newsapi/internal/requestid"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// MaxIdempotencyKeyLength is the longest idempotency key accepted.
+	MaxIdempotencyKeyLength = 255
+	// MaxIdempotentRequestSize is the largest request body accepted with an
+	// idempotency key.
+	MaxIdempotentRequestSize = 1 << 20
+	// idempotencyStoreTimeout bounds the time taken to store the response or
+	// release the key once the request is served.
+	idempotencyStoreTimeout = 5 * time.Second
+)
+
+var (
+	errIdempotencyKeyReused   = errors.New("the idempotency key was used with another request")
+	errIdempotencyKeyInFlight = errors.New("a request with the idempotency key is in progress")
+)
+
+// Idempotent makes the requests sent with an Idempotency-Key header safe to
+// retry. The first response for a key is stored and replayed for the
+// following requests with the same key and body. Reusing the key with
+// another body is rejected with 422, and a retry while the first request is
+// in progress with 409. Server errors are not stored, so that the request
+// can be retried.
+func Idempotent(is IdempotencyStorer, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		log := logger.FromContext(ctx).With("idempotencyKey", key)
+		if err := validateIdempotencyKey(key); err != nil {
+			log.Error("invalid idempotency key", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidIdempotency, err)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxIdempotentRequestSize))
+		if err != nil {
+			log.Error("failed to read the request", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped to the client, so that clients cannot replay the
+		// responses of each other.
+		if p, ok := auth.FromContext(ctx); ok {
+			key = p.Subject + "/" + key
+		}
+		fingerprint := requestFingerprint(r, body)
+		rec, reserved, err := is.Reserve(ctx, key, fingerprint)
+		if err != nil {
+			log.Error("failed to reserve the idempotency key", "error", err)
+			writeProblem(w, r, http.StatusInternalServerError, CodeInternal, err)
+			return
+		}
+		if !reserved {
+			switch {
+			case rec.Fingerprint != fingerprint:
+				log.Error("idempotency key reused with another request")
+				writeProblem(w, r, http.StatusUnprocessableEntity, CodeIdempotencyReused, errIdempotencyKeyReused)
+			case !rec.Completed():
+				log.Error("idempotency key in progress")
+				writeProblem(w, r, http.StatusConflict, CodeIdempotencyInFlight, errIdempotencyKeyInFlight)
+			default:
+				log.Info("replaying the stored response")
+				for k, v := range rec.Header {
+					// The request ID identifies the retry, not the first
+					// request.
+					if k == http.CanonicalHeaderKey(requestid.Header) {
+						continue
+					}
+					w.Header()[k] = v
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(rec.StatusCode)
+				if _, err := w.Write(rec.Body); err != nil {
+					log.Error("failed to write response", "error", err)
+				}
+			}
+			return
+		}
+
+		rw := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		// The client may be gone, e.g. on a timeout it retries after, but
+		// the key must still be completed or released for the retry.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyStoreTimeout)
+		defer cancel()
+		if rw.status >= http.StatusInternalServerError {
+			if err := is.Release(ctx, rec); err != nil {
+				log.Error("failed to release the idempotency key", "error", err)
+			}
+			return
+		}
+		rec.StatusCode = rw.status
+		rec.Header = rw.Header().Clone()
+		rec.Header.Del(requestid.Header)
+		rec.Body = rw.body.Bytes()
+		if err := is.Complete(ctx, rec); err != nil {
+			log.Error("failed to store the response", "error", err)
+		}
+	}
+}
+
+// validateIdempotencyKey checks the key is made of visible ASCII characters.
+func validateIdempotencyKey(key string) error {
+	if len(key) > MaxIdempotencyKeyLength {
+		return fmt.Errorf("idempotency key cannot be longer than %d characters", MaxIdempotencyKeyLength)
+	}
+	for _, c := range []byte(key) {
+		if c < '!' || c > '~' {
+			return errors.New("idempotency key must only contain visible ASCII characters")
+		}
+	}
+	return nil
+}
+
+// requestFingerprint identifies the request a key was used with.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.Path)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder writes the response and keeps a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *responseRecorder) WriteHeader(status int) {
+	if !rw.wroteHeader {
+		rw.status = status
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseRecorder) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
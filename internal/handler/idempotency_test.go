@@ -0,0 +1,240 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prashsamosa/newsapi/internal/handler"
+	mockshandler "github.com/prashsamosa/newsapi/internal/handler/mocks"
+	"github.com/prashsamosa/newsapi/internal/idempotency"
+	"github.com/prashsamosa/newsapi/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Idempotent(t *testing.T) {
+	created := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Location", "/news/1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ID":"1"}`))
+	})
+	failed := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	testCases := []struct {
+		name             string
+		key              string
+		next             http.Handler
+		setup            func(testing.TB) *mockshandler.MockIdempotencyStorer
+		expectedStatus   int
+		expectedBody     string
+		expectedReplayed bool
+	}{
+		{
+			name: "no key",
+			next: created,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				return mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"ID":"1"}`,
+		},
+		{
+			name: "invalid key",
+			key:  "key with spaces",
+			next: created,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				return mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "db error",
+			key:  "key",
+			next: created,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+				ms.EXPECT().Reserve(gomock.Any(), "key", gomock.Any()).Return(nil, false, errors.New("db error"))
+				return ms
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "first request",
+			key:  "key",
+			next: created,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+				ms.EXPECT().Reserve(gomock.Any(), "key", gomock.Any()).Return(&idempotency.Record{Key: "key"}, true, nil)
+				ms.EXPECT().Complete(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec *idempotency.Record) error {
+					assert.Equal(t, http.StatusCreated, rec.StatusCode)
+					assert.Equal(t, "/news/1", rec.Header.Get("Location"))
+					assert.Empty(t, rec.Header.Get(requestid.Header))
+					assert.JSONEq(t, `{"ID":"1"}`, string(rec.Body))
+					return nil
+				})
+				return ms
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"ID":"1"}`,
+		},
+		{
+			name: "server error releases the key",
+			key:  "key",
+			next: failed,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+				rec := &idempotency.Record{Key: "key"}
+				ms.EXPECT().Reserve(gomock.Any(), "key", gomock.Any()).Return(rec, true, nil)
+				ms.EXPECT().Release(gomock.Any(), rec).Return(nil)
+				return ms
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "replayed",
+			key:  "key",
+			next: failed,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+				ms.EXPECT().Reserve(gomock.Any(), "key", gomock.Any()).DoAndReturn(
+					func(_ context.Context, key, fingerprint string) (*idempotency.Record, bool, error) {
+						return &idempotency.Record{
+							Key:         key,
+							Fingerprint: fingerprint,
+							StatusCode:  http.StatusCreated,
+							Header:      http.Header{"Location": {"/news/1"}, "X-Request-Id": {"first-request-id"}},
+							Body:        []byte(`{"ID":"1"}`),
+						}, false, nil
+					})
+				return ms
+			},
+			expectedStatus:   http.StatusCreated,
+			expectedBody:     `{"ID":"1"}`,
+			expectedReplayed: true,
+		},
+		{
+			name: "key reused with another request",
+			key:  "key",
+			next: created,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+				ms.EXPECT().Reserve(gomock.Any(), "key", gomock.Any()).
+					Return(&idempotency.Record{Key: "key", Fingerprint: "other", StatusCode: http.StatusCreated}, false, nil)
+				return ms
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "key in progress",
+			key:  "key",
+			next: created,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+				ms.EXPECT().Reserve(gomock.Any(), "key", gomock.Any()).DoAndReturn(
+					func(_ context.Context, key, fingerprint string) (*idempotency.Record, bool, error) {
+						return &idempotency.Record{Key: key, Fingerprint: fingerprint}, false, nil
+					})
+				return ms
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			// Set by the request ID middleware.
+			w.Header().Set(requestid.Header, "request-id")
+			r := httptest.NewRequest(http.MethodPost, "/news", strings.NewReader(`{"title":"first news"}`))
+			if tc.key != "" {
+				r.Header.Set(handler.IdempotencyKeyHeader, tc.key)
+			}
+
+			// Act
+			handler.Idempotent(tc.setup(t), tc.next)(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			}
+			assert.Equal(t, tc.expectedReplayed, w.Result().Header.Get("Idempotent-Replayed") == "true")
+			assert.Equal(t, "request-id", w.Result().Header.Get(requestid.Header))
+		})
+	}
+}
+
+func Test_Idempotent_ClientGone(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status int
+		setup  func(testing.TB) *mockshandler.MockIdempotencyStorer
+	}{
+		{
+			name:   "response stored",
+			status: http.StatusCreated,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+				ms.EXPECT().Reserve(gomock.Any(), "key", gomock.Any()).Return(&idempotency.Record{Key: "key"}, true, nil)
+				ms.EXPECT().Complete(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ *idempotency.Record) error {
+					assert.NoError(t, ctx.Err())
+					return nil
+				})
+				return ms
+			},
+		},
+		{
+			name:   "key released",
+			status: http.StatusInternalServerError,
+			setup: func(tb testing.TB) *mockshandler.MockIdempotencyStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockIdempotencyStorer(gomock.NewController(t))
+				ms.EXPECT().Reserve(gomock.Any(), "key", gomock.Any()).Return(&idempotency.Record{Key: "key"}, true, nil)
+				ms.EXPECT().Release(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ *idempotency.Record) error {
+					assert.NoError(t, ctx.Err())
+					return nil
+				})
+				return ms
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			w := httptest.NewRecorder()
+			r := httptest.NewRequestWithContext(ctx, http.MethodPost, "/news", strings.NewReader(`{"title":"first news"}`))
+			r.Header.Set(handler.IdempotencyKeyHeader, "key")
+			// The client disconnects while the request is served.
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				cancel()
+				w.WriteHeader(tc.status)
+			})
+			ms := tc.setup(t)
+
+			// Act
+			handler.Idempotent(ms, next)(w, r)
+
+			// Assert
+			assert.Equal(t, tc.status, w.Result().StatusCode)
+		})
+	}
+}
@@ -13,8 +13,10 @@ import (
 	context "context"
 	reflect "reflect"
 
-	news "github.com/prashsamosa/newsapi/internal/news"
 	uuid "github.com/google/uuid"
+	auth "github.com/prashsamosa/newsapi/internal/auth"
+	idempotency "github.com/prashsamosa/newsapi/internal/idempotency"
+	news "github.com/prashsamosa/newsapi/internal/news"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,6 +44,21 @@ func (m *MockNewsStorer) EXPECT() *MockNewsStorerMockRecorder {
 	return m.recorder
 }
 
+// Batch mocks base method.
+func (m *MockNewsStorer) Batch(ctx context.Context, ops []news.BatchOperation, atomic bool) ([]news.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Batch", ctx, ops, atomic)
+	ret0, _ := ret[0].([]news.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch.
+func (mr *MockNewsStorerMockRecorder) Batch(ctx, ops, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockNewsStorer)(nil).Batch), ctx, ops, atomic)
+}
+
 // Create mocks base method.
 func (m *MockNewsStorer) Create(arg0 context.Context, arg1 *news.Record) (*news.Record, error) {
 	m.ctrl.T.Helper()
@@ -58,47 +75,62 @@ func (mr *MockNewsStorerMockRecorder) Create(arg0, arg1 any) *gomock.Call {
 }
 
 // DeleteByID mocks base method.
-func (m *MockNewsStorer) DeleteByID(arg0 context.Context, arg1 uuid.UUID) error {
+func (m *MockNewsStorer) DeleteByID(arg0 context.Context, arg1 uuid.UUID, arg2 news.DeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID.
-func (mr *MockNewsStorerMockRecorder) DeleteByID(arg0, arg1 any) *gomock.Call {
+func (mr *MockNewsStorerMockRecorder) DeleteByID(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockNewsStorer)(nil).DeleteByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockNewsStorer)(nil).DeleteByID), arg0, arg1, arg2)
 }
 
-// FindAll mocks base method.
-func (m *MockNewsStorer) FindAll(arg0 context.Context) ([]*news.Record, error) {
+// FindByID mocks base method.
+func (m *MockNewsStorer) FindByID(arg0 context.Context, arg1 uuid.UUID) (*news.Record, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll", arg0)
-	ret0, _ := ret[0].([]*news.Record)
+	ret := m.ctrl.Call(m, "FindByID", arg0, arg1)
+	ret0, _ := ret[0].(*news.Record)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindAll indicates an expected call of FindAll.
-func (mr *MockNewsStorerMockRecorder) FindAll(arg0 any) *gomock.Call {
+// FindByID indicates an expected call of FindByID.
+func (mr *MockNewsStorerMockRecorder) FindByID(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockNewsStorer)(nil).FindAll), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockNewsStorer)(nil).FindByID), arg0, arg1)
 }
 
-// FindByID mocks base method.
-func (m *MockNewsStorer) FindByID(arg0 context.Context, arg1 uuid.UUID) (*news.Record, error) {
+// FindPage mocks base method.
+func (m *MockNewsStorer) FindPage(arg0 context.Context, arg1 news.ListParams) (*news.Page, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", arg0, arg1)
-	ret0, _ := ret[0].(*news.Record)
+	ret := m.ctrl.Call(m, "FindPage", arg0, arg1)
+	ret0, _ := ret[0].(*news.Page)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindByID indicates an expected call of FindByID.
-func (mr *MockNewsStorerMockRecorder) FindByID(arg0, arg1 any) *gomock.Call {
+// FindPage indicates an expected call of FindPage.
+func (mr *MockNewsStorerMockRecorder) FindPage(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockNewsStorer)(nil).FindByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockNewsStorer)(nil).FindPage), arg0, arg1)
+}
+
+// Search mocks base method.
+func (m *MockNewsStorer) Search(arg0 context.Context, arg1 news.SearchParams) ([]*news.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", arg0, arg1)
+	ret0, _ := ret[0].([]*news.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockNewsStorerMockRecorder) Search(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockNewsStorer)(nil).Search), arg0, arg1)
 }
 
 // UpdateByID mocks base method.
@@ -114,3 +146,342 @@ func (mr *MockNewsStorerMockRecorder) UpdateByID(arg0, arg1, arg2 any) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateByID", reflect.TypeOf((*MockNewsStorer)(nil).UpdateByID), arg0, arg1, arg2)
 }
+
+// MockTrashStorer is a mock of TrashStorer interface.
+type MockTrashStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashStorerMockRecorder
+	isgomock struct{}
+}
+
+// MockTrashStorerMockRecorder is the mock recorder for MockTrashStorer.
+type MockTrashStorerMockRecorder struct {
+	mock *MockTrashStorer
+}
+
+// NewMockTrashStorer creates a new mock instance.
+func NewMockTrashStorer(ctrl *gomock.Controller) *MockTrashStorer {
+	mock := &MockTrashStorer{ctrl: ctrl}
+	mock.recorder = &MockTrashStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashStorer) EXPECT() *MockTrashStorerMockRecorder {
+	return m.recorder
+}
+
+// FindTrash mocks base method.
+func (m *MockTrashStorer) FindTrash(ctx context.Context, limit, offset int) (*news.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrash", ctx, limit, offset)
+	ret0, _ := ret[0].(*news.Page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrash indicates an expected call of FindTrash.
+func (mr *MockTrashStorerMockRecorder) FindTrash(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrash", reflect.TypeOf((*MockTrashStorer)(nil).FindTrash), ctx, limit, offset)
+}
+
+// PurgeByID mocks base method.
+func (m *MockTrashStorer) PurgeByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeByID indicates an expected call of PurgeByID.
+func (mr *MockTrashStorerMockRecorder) PurgeByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeByID", reflect.TypeOf((*MockTrashStorer)(nil).PurgeByID), arg0, arg1)
+}
+
+// RestoreByID mocks base method.
+func (m *MockTrashStorer) RestoreByID(arg0 context.Context, arg1 uuid.UUID) (*news.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreByID", arg0, arg1)
+	ret0, _ := ret[0].(*news.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreByID indicates an expected call of RestoreByID.
+func (mr *MockTrashStorerMockRecorder) RestoreByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreByID", reflect.TypeOf((*MockTrashStorer)(nil).RestoreByID), arg0, arg1)
+}
+
+// MockStatusStorer is a mock of StatusStorer interface.
+type MockStatusStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusStorerMockRecorder
+	isgomock struct{}
+}
+
+// MockStatusStorerMockRecorder is the mock recorder for MockStatusStorer.
+type MockStatusStorerMockRecorder struct {
+	mock *MockStatusStorer
+}
+
+// NewMockStatusStorer creates a new mock instance.
+func NewMockStatusStorer(ctrl *gomock.Controller) *MockStatusStorer {
+	mock := &MockStatusStorer{ctrl: ctrl}
+	mock.recorder = &MockStatusStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusStorer) EXPECT() *MockStatusStorerMockRecorder {
+	return m.recorder
+}
+
+// Transition mocks base method.
+func (m *MockStatusStorer) Transition(ctx context.Context, id uuid.UUID, to news.Status, version int) (*news.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", ctx, id, to, version)
+	ret0, _ := ret[0].(*news.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockStatusStorerMockRecorder) Transition(ctx, id, to, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockStatusStorer)(nil).Transition), ctx, id, to, version)
+}
+
+// MockAuditStorer is a mock of AuditStorer interface.
+type MockAuditStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStorerMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditStorerMockRecorder is the mock recorder for MockAuditStorer.
+type MockAuditStorerMockRecorder struct {
+	mock *MockAuditStorer
+}
+
+// NewMockAuditStorer creates a new mock instance.
+func NewMockAuditStorer(ctrl *gomock.Controller) *MockAuditStorer {
+	mock := &MockAuditStorer{ctrl: ctrl}
+	mock.recorder = &MockAuditStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStorer) EXPECT() *MockAuditStorerMockRecorder {
+	return m.recorder
+}
+
+// FindAudit mocks base method.
+func (m *MockAuditStorer) FindAudit(arg0 context.Context, arg1 news.AuditFilter) ([]*news.AuditEntry, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAudit", arg0, arg1)
+	ret0, _ := ret[0].([]*news.AuditEntry)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindAudit indicates an expected call of FindAudit.
+func (mr *MockAuditStorerMockRecorder) FindAudit(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAudit", reflect.TypeOf((*MockAuditStorer)(nil).FindAudit), arg0, arg1)
+}
+
+// MockRevisionStorer is a mock of RevisionStorer interface.
+type MockRevisionStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionStorerMockRecorder
+	isgomock struct{}
+}
+
+// MockRevisionStorerMockRecorder is the mock recorder for MockRevisionStorer.
+type MockRevisionStorerMockRecorder struct {
+	mock *MockRevisionStorer
+}
+
+// NewMockRevisionStorer creates a new mock instance.
+func NewMockRevisionStorer(ctrl *gomock.Controller) *MockRevisionStorer {
+	mock := &MockRevisionStorer{ctrl: ctrl}
+	mock.recorder = &MockRevisionStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevisionStorer) EXPECT() *MockRevisionStorerMockRecorder {
+	return m.recorder
+}
+
+// DiffRevisions mocks base method.
+func (m *MockRevisionStorer) DiffRevisions(ctx context.Context, id uuid.UUID, from, to int) (map[string]news.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", ctx, id, from, to)
+	ret0, _ := ret[0].(map[string]news.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockRevisionStorerMockRecorder) DiffRevisions(ctx, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockRevisionStorer)(nil).DiffRevisions), ctx, id, from, to)
+}
+
+// FindRevision mocks base method.
+func (m *MockRevisionStorer) FindRevision(ctx context.Context, id uuid.UUID, revision int) (*news.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevision", ctx, id, revision)
+	ret0, _ := ret[0].(*news.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevision indicates an expected call of FindRevision.
+func (mr *MockRevisionStorerMockRecorder) FindRevision(ctx, id, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevision", reflect.TypeOf((*MockRevisionStorer)(nil).FindRevision), ctx, id, revision)
+}
+
+// FindRevisions mocks base method.
+func (m *MockRevisionStorer) FindRevisions(ctx context.Context, id uuid.UUID, limit, offset int) ([]*news.Revision, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevisions", ctx, id, limit, offset)
+	ret0, _ := ret[0].([]*news.Revision)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindRevisions indicates an expected call of FindRevisions.
+func (mr *MockRevisionStorerMockRecorder) FindRevisions(ctx, id, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevisions", reflect.TypeOf((*MockRevisionStorer)(nil).FindRevisions), ctx, id, limit, offset)
+}
+
+// RestoreRevision mocks base method.
+func (m *MockRevisionStorer) RestoreRevision(ctx context.Context, id uuid.UUID, revision, version int) (*news.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", ctx, id, revision, version)
+	ret0, _ := ret[0].(*news.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockRevisionStorerMockRecorder) RestoreRevision(ctx, id, revision, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockRevisionStorer)(nil).RestoreRevision), ctx, id, revision, version)
+}
+
+// MockIdempotencyStorer is a mock of IdempotencyStorer interface.
+type MockIdempotencyStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyStorerMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyStorerMockRecorder is the mock recorder for MockIdempotencyStorer.
+type MockIdempotencyStorerMockRecorder struct {
+	mock *MockIdempotencyStorer
+}
+
+// NewMockIdempotencyStorer creates a new mock instance.
+func NewMockIdempotencyStorer(ctrl *gomock.Controller) *MockIdempotencyStorer {
+	mock := &MockIdempotencyStorer{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyStorer) EXPECT() *MockIdempotencyStorerMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyStorer) Complete(arg0 context.Context, arg1 *idempotency.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyStorerMockRecorder) Complete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyStorer)(nil).Complete), arg0, arg1)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyStorer) Release(arg0 context.Context, arg1 *idempotency.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyStorerMockRecorder) Release(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyStorer)(nil).Release), arg0, arg1)
+}
+
+// Reserve mocks base method.
+func (m *MockIdempotencyStorer) Reserve(ctx context.Context, key, fingerprint string) (*idempotency.Record, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, key, fingerprint)
+	ret0, _ := ret[0].(*idempotency.Record)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIdempotencyStorerMockRecorder) Reserve(ctx, key, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIdempotencyStorer)(nil).Reserve), ctx, key, fingerprint)
+}
+
+// MockAuthenticator is a mock of Authenticator interface.
+type MockAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthenticatorMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthenticatorMockRecorder is the mock recorder for MockAuthenticator.
+type MockAuthenticatorMockRecorder struct {
+	mock *MockAuthenticator
+}
+
+// NewMockAuthenticator creates a new mock instance.
+func NewMockAuthenticator(ctrl *gomock.Controller) *MockAuthenticator {
+	mock := &MockAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthenticator) EXPECT() *MockAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuthenticator) Authenticate(ctx context.Context, credentials string) (*auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, credentials)
+	ret0, _ := ret[0].(*auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthenticatorMockRecorder) Authenticate(ctx, credentials any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthenticator)(nil).Authenticate), ctx, credentials)
+}
@@ -1,9 +1,6 @@
 package handler
 
 import (
-	"errors"
-	"fmt"
-	"net/url"
 	"time"
 
 	"github.com/prashsamosa/newsapi/internal/news"
@@ -20,53 +17,110 @@ type NewsPostReqBody struct {
 	Content   string    `json:"content"`
 	Source    string    `json:"source"`
 	Tags      []string  `json:"tags"`
+	PublishAt string    `json:"publish_at,omitempty"`
+	ExpireAt  string    `json:"expire_at,omitempty"`
 }
 
-// Validate the incoming request.
-func (n *NewsPostReqBody) Validate() (record *news.Record, errs error) {
-	if n.Author == "" {
-		errs = errors.Join(errs, fmt.Errorf("author is empty: %s", n.Author))
-	}
-	if n.Title == "" {
-		errs = errors.Join(errs, fmt.Errorf("title is empty: %s", n.Title))
+// NewNewsPostReqBody returns the request body representation of a record.
+func NewNewsPostReqBody(n *news.Record) NewsPostReqBody {
+	body := NewsPostReqBody{
+		ID:        n.ID,
+		Author:    n.Author,
+		Title:     n.Title,
+		Summary:   n.Summary,
+		CreatedAt: n.CreatedAt.Format(time.RFC3339Nano),
+		Content:   n.Content,
+		Source:    n.Source,
+		Tags:      n.Tags,
 	}
-	if n.Content == "" {
-		errs = errors.Join(errs, fmt.Errorf("content is empty: %s", n.Content))
+	if !n.PublishAt.IsZero() {
+		body.PublishAt = n.PublishAt.Format(time.RFC3339Nano)
 	}
-	if n.Summary == "" {
-		errs = errors.Join(errs, fmt.Errorf("summary is empty: %s", n.Summary))
+	if !n.ExpireAt.IsZero() {
+		body.ExpireAt = n.ExpireAt.Format(time.RFC3339Nano)
 	}
-	t, err := time.Parse(time.RFC3339, n.CreatedAt)
-	if err != nil {
-		errs = errors.Join(errs, err)
+	return body
+}
+
+// AllNewsResponse represents the all news response.
+type AllNewsResponse struct {
+	News       []*news.Record `json:"news"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	PrevCursor string         `json:"prev_cursor,omitempty"`
+	// TotalHint is the number of news matching the listing. It is a hint
+	// only, as news can be added or removed while paging.
+	TotalHint int `json:"total_hint"`
+}
+
+// NewAllNewsResponse builds the response from a page of news.
+func NewAllNewsResponse(page *news.Page) AllNewsResponse {
+	if page == nil {
+		return AllNewsResponse{}
 	}
-	if n.Source == "" {
-		errs = errors.Join(errs, fmt.Errorf("source is empty: %s", n.Source))
+	resp := AllNewsResponse{
+		News:      page.Records,
+		TotalHint: page.TotalHint,
 	}
-	parsedURL, err := url.Parse(n.Source)
-	if err != nil {
-		errs = errors.Join(errs, err)
+	if page.NextCursor != nil {
+		resp.NextCursor = page.NextCursor.Encode()
 	}
-	if len(n.Tags) == 0 {
-		errs = errors.Join(errs, errors.New("tags cannot be empty"))
+	if page.PrevCursor != nil {
+		resp.PrevCursor = page.PrevCursor.Encode()
 	}
+	return resp
+}
 
-	if errs != nil {
-		return record, errs
-	}
-	return &news.Record{
-		ID:        n.ID,
-		Author:    n.Author,
-		Title:     n.Title,
-		Content:   n.Content,
-		Summary:   n.Summary,
-		CreatedAt: t,
-		Source:    parsedURL.String(),
-		Tags:      n.Tags,
-	}, nil
+// SearchNewsResponse represents the news search response.
+type SearchNewsResponse struct {
+	Results []*news.SearchResult `json:"results"`
 }
 
-// AllNewsResponse represents the all news response.
-type AllNewsResponse struct {
-	News []*news.Record `json:"news"`
+// BatchReqBody represents the request body of a news batch.
+type BatchReqBody struct {
+	// Atomic applies all the operations or none of them.
+	Atomic     bool                    `json:"atomic"`
+	Operations []BatchOperationReqBody `json:"operations"`
+}
+
+// BatchOperationReqBody represents a single operation of a news batch.
+type BatchOperationReqBody struct {
+	Op      news.BatchOp     `json:"op"`
+	ID      uuid.UUID        `json:"id"`
+	Version int              `json:"version"`
+	News    *NewsPostReqBody `json:"news"`
+}
+
+// BatchResponse represents the news batch response, with a result for each
+// operation in the order of the request.
+type BatchResponse struct {
+	Results []BatchItemResponse `json:"results"`
+}
+
+// BatchItemResponse represents the result of a batch operation.
+type BatchItemResponse struct {
+	Index  int          `json:"index"`
+	Status int          `json:"status"`
+	News   *news.Record `json:"news,omitempty"`
+	Error  *Problem     `json:"error,omitempty"`
+}
+
+// AuditResponse represents the audit log response.
+type AuditResponse struct {
+	Entries []*news.AuditEntry `json:"entries"`
+	// TotalHint is the number of entries matching the filter.
+	TotalHint int `json:"total_hint"`
+}
+
+// RevisionsResponse represents the news revisions response.
+type RevisionsResponse struct {
+	Revisions []*news.Revision `json:"revisions"`
+	// TotalHint is the number of revisions of the news.
+	TotalHint int `json:"total_hint"`
+}
+
+// RevisionDiffResponse represents the changes between two revisions.
+type RevisionDiffResponse struct {
+	From    int                    `json:"from"`
+	To      int                    `json:"to"`
+	Changes map[string]news.Change `json:"changes"`
 }
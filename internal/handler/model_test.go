@@ -2,11 +2,13 @@ package handler_test
 
 import (
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/prashsamosa/newsapi/internal/handler"
 	"github.com/prashsamosa/newsapi/internal/news"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -98,6 +100,126 @@ func TestNewsPostReqBody_Validate(t *testing.T) {
 				err: "invalid port",
 			},
 		},
+		{
+			name: "source without scheme",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     "test-title",
+				Summary:   "test-summary",
+				CreatedAt: "2024-04-07T05:13:27+00:00",
+				Source:    "foo",
+				Content:   "test-content",
+				Tags:      []string{"test-tag"},
+			},
+			expectations: expectations{
+				err: "source must be an http or https url",
+			},
+		},
+		{
+			name: "source not http",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     "test-title",
+				Summary:   "test-summary",
+				CreatedAt: "2024-04-07T05:13:27+00:00",
+				Source:    "ftp://test-news.com/file",
+				Content:   "test-content",
+				Tags:      []string{"test-tag"},
+			},
+			expectations: expectations{
+				err: "source must be an http or https url",
+			},
+		},
+		{
+			name: "source without host",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     "test-title",
+				Summary:   "test-summary",
+				CreatedAt: "2024-04-07T05:13:27+00:00",
+				Source:    "https:///path",
+				Content:   "test-content",
+				Tags:      []string{"test-tag"},
+			},
+			expectations: expectations{
+				err: "source must be an absolute url with a host",
+			},
+		},
+		{
+			name: "title too long",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     strings.Repeat("a", handler.MaxTitleLength+1),
+				Summary:   "test-summary",
+				CreatedAt: "2024-04-07T05:13:27+00:00",
+				Source:    "https://test-news.com",
+				Content:   "test-content",
+				Tags:      []string{"test-tag"},
+			},
+			expectations: expectations{
+				err: "title cannot be longer than 300 characters",
+			},
+		},
+		{
+			name: "created_at in the future",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     "test-title",
+				Summary:   "test-summary",
+				CreatedAt: "3024-04-07T05:13:27+00:00",
+				Source:    "https://test-news.com",
+				Content:   "test-content",
+				Tags:      []string{"test-tag"},
+			},
+			expectations: expectations{
+				err: "created_at cannot be in the future",
+			},
+		},
+		{
+			name: "too many tags",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     "test-title",
+				Summary:   "test-summary",
+				CreatedAt: "2024-04-07T05:13:27+00:00",
+				Source:    "https://test-news.com",
+				Content:   "test-content",
+				Tags:      strings.Split(strings.Repeat("tag,", handler.MaxTags)+"tag", ","),
+			},
+			expectations: expectations{
+				err: "tags cannot have more than 20 tags",
+			},
+		},
+		{
+			name: "invalid tag format",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     "test-title",
+				Summary:   "test-summary",
+				CreatedAt: "2024-04-07T05:13:27+00:00",
+				Source:    "https://test-news.com",
+				Content:   "test-content",
+				Tags:      []string{"politics", "#elections"},
+			},
+			expectations: expectations{
+				err: "tags[1] must start with a letter or digit",
+			},
+		},
+		{
+			name: "duplicate tags",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     "test-title",
+				Summary:   "test-summary",
+				CreatedAt: "2024-04-07T05:13:27+00:00",
+				Source:    "https://test-news.com",
+				Content:   "test-content",
+				Tags:      []string{"politics", "Politics"},
+			},
+			expectations: expectations{
+				err: `tags[1] duplicates tags[0]: "Politics"`,
+			},
+		},
 		{
 			name: "tags empty",
 			req: handler.NewsPostReqBody{
@@ -112,6 +234,39 @@ func TestNewsPostReqBody_Validate(t *testing.T) {
 				err: "tags cannot be empty",
 			},
 		},
+		{
+			name: "publish_at invalid",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     "test-title",
+				Summary:   "test-summary",
+				CreatedAt: "2024-04-07T05:13:27+00:00",
+				Source:    "https://test-news.com",
+				Content:   "test-content",
+				Tags:      []string{"test-tag"},
+				PublishAt: "tomorrow",
+			},
+			expectations: expectations{
+				err: "publish_at is not a valid RFC3339 timestamp",
+			},
+		},
+		{
+			name: "expire_at before publish_at",
+			req: handler.NewsPostReqBody{
+				Author:    "test-author",
+				Title:     "test-title",
+				Summary:   "test-summary",
+				CreatedAt: "2024-04-07T05:13:27+00:00",
+				Source:    "https://test-news.com",
+				Content:   "test-content",
+				Tags:      []string{"test-tag"},
+				PublishAt: "2024-05-01T00:00:00Z",
+				ExpireAt:  "2024-04-01T00:00:00Z",
+			},
+			expectations: expectations{
+				err: "expire_at must be after publish_at",
+			},
+		},
 		{
 			name: "validate",
 			req: handler.NewsPostReqBody{
@@ -160,3 +315,41 @@ func TestNewsPostReqBody_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAllNewsResponse(t *testing.T) {
+	next := &news.Cursor{CreatedAt: time.Now(), ID: uuid.New()}
+	resp := handler.NewAllNewsResponse(&news.Page{
+		Records:    []*news.Record{{ID: next.ID}},
+		NextCursor: next,
+		TotalHint:  10,
+	})
+
+	assert.Len(t, resp.News, 1)
+	assert.Empty(t, resp.PrevCursor)
+	assert.Equal(t, 10, resp.TotalHint)
+	c, err := news.DecodeCursor(resp.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, next.ID, c.ID)
+	assert.True(t, next.CreatedAt.Equal(c.CreatedAt))
+	assert.False(t, c.Backward)
+}
+
+func TestNewsPostReqBody_Validate_FieldErrors(t *testing.T) {
+	req := handler.NewsPostReqBody{
+		Author:    "test-author",
+		Title:     "test-title",
+		Summary:   "test-summary",
+		CreatedAt: "2024-04-07T05:13:27+00:00",
+		Source:    "foo",
+		Tags:      []string{"a", "a"},
+	}
+
+	_, err := req.Validate()
+
+	var fieldErr *handler.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "content", fieldErr.Field)
+	assert.Equal(t, handler.FieldCodeRequired, fieldErr.Code)
+	assert.ErrorContains(t, err, "source must be an http or https url")
+	assert.ErrorContains(t, err, "tags[1] duplicates tags[0]")
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	// MergePatchContentType is the media type of a JSON Merge Patch (RFC 7396).
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType is the media type of a JSON Patch (RFC 6902).
+	JSONPatchContentType = "application/json-patch+json"
+	// MaxPatchSize is the largest patch document accepted, in bytes.
+	MaxPatchSize = 1 << 20
+)
+
+// parsePatchType returns the patch media type from the Content-Type header.
+func parsePatchType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("parse content type: %w", err)
+	}
+	switch mediaType {
+	case MergePatchContentType, JSONPatchContentType:
+		return mediaType, nil
+	default:
+		return "", fmt.Errorf("unsupported patch content type: %s", mediaType)
+	}
+}
+
+// applyPatch applies the patch document to the request body of the news.
+func applyPatch(patchType string, body NewsPostReqBody, patch []byte) (NewsPostReqBody, error) {
+	doc, err := json.Marshal(body)
+	if err != nil {
+		return NewsPostReqBody{}, fmt.Errorf("marshal news: %w", err)
+	}
+
+	var patched []byte
+	if patchType == JSONPatchContentType {
+		p, decodeErr := jsonpatch.DecodePatch(patch)
+		if decodeErr != nil {
+			return NewsPostReqBody{}, fmt.Errorf("decode json patch: %w", decodeErr)
+		}
+		patched, err = p.Apply(doc)
+	} else {
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	}
+	if err != nil {
+		return NewsPostReqBody{}, fmt.Errorf("apply patch: %w", err)
+	}
+
+	var result NewsPostReqBody
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&result); err != nil {
+		return NewsPostReqBody{}, fmt.Errorf("decode patched news: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/prashsamosa/newsapi/internal/logger"
+	"github.com/prashsamosa/newsapi/internal/news"
+	"github.com/prashsamosa/newsapi/internal/requestid"
+)
+
+// ProblemContentType is the media type of the error responses.
+const ProblemContentType = "application/problem+json"
+
+// Error codes of the problems raised by the handlers.
+const (
+	CodeInvalidBody          = "invalid_request_body"
+	CodeValidationFailed     = "validation_failed"
+	CodeInvalidNewsID        = "invalid_news_id"
+	CodeInvalidRevision      = "invalid_revision"
+	CodeInvalidQuery         = "invalid_query"
+	CodePreconditionFailed   = "precondition_failed"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeInvalidIdempotency   = "invalid_idempotency_key"
+	CodeIdempotencyReused    = "idempotency_key_reused"
+	CodeIdempotencyInFlight  = "idempotency_key_in_progress"
+	CodeUnauthenticated      = "unauthenticated"
+	CodeForbidden            = "forbidden"
+	CodeInternal             = "internal_error"
+)
+
+// problemTypePrefix prefixes the error code to build the problem type URI.
+const problemTypePrefix = "urn:newsapi:problem:"
+
+// Problem is an RFC 7807 problem details error response.
+type Problem struct {
+	Type      string        `json:"type"`
+	Title     string        `json:"title"`
+	Status    int           `json:"status"`
+	Detail    string        `json:"detail,omitempty"`
+	Instance  string        `json:"instance,omitempty"`
+	Code      string        `json:"code"`
+	RequestID string        `json:"request_id,omitempty"`
+	Errors    []*FieldError `json:"errors,omitempty"`
+}
+
+// writeProblem writes the error as a problem details response.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code string, err error) {
+	p := newProblem(r, status, code, err)
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		logger.FromContext(r.Context()).Error("failed to write problem", "error", err)
+	}
+}
+
+// newProblem returns the problem details of the error. Field errors wrapped
+// in err are reported in the errors member. The detail of server errors is
+// not exposed to the client.
+func newProblem(r *http.Request, status int, code string, err error) *Problem {
+	p := &Problem{
+		Type:      problemTypePrefix + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: requestid.FromContext(r.Context()),
+		Errors:    fieldErrors(err),
+	}
+	switch {
+	case status >= http.StatusInternalServerError:
+		p.Detail = "the server failed to process the request"
+	case len(p.Errors) > 0:
+		p.Detail = "the request has invalid fields"
+	case err != nil:
+		p.Detail = err.Error()
+	}
+	return p
+}
+
+// writeStoreError writes an error returned by the store.
+func writeStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := storeErrorStatus(err)
+	writeProblem(w, r, status, code, err)
+}
+
+// storeErrorStatus returns the status and code of an error returned by the
+// store, using the ones of a news.CustomError when available.
+func storeErrorStatus(err error) (status int, code string) {
+	var dbErr *news.CustomError
+	if errors.As(err, &dbErr) {
+		return dbErr.HTTPStatusCode(), dbErr.Code()
+	}
+	return http.StatusInternalServerError, CodeInternal
+}
+
+// fieldErrors returns the field errors wrapped in err, including the ones
+// joined with errors.Join.
+func fieldErrors(err error) []*FieldError {
+	if err == nil {
+		return nil
+	}
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		var errs []*FieldError
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, fieldErrors(e)...)
+		}
+		return errs
+	}
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return []*FieldError{fe}
+	}
+	return nil
+}
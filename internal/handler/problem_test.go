@@ -0,0 +1,114 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prashsamosa/newsapi/internal/handler"
+	mockshandler "github.com/prashsamosa/newsapi/internal/handler/mocks"
+	"github.com/prashsamosa/newsapi/internal/news"
+	"github.com/prashsamosa/newsapi/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Problem(t *testing.T) {
+	testCases := []struct {
+		name            string
+		handler         func(ms *mockshandler.MockNewsStorer) http.HandlerFunc
+		request         func() *http.Request
+		expectedProblem handler.Problem
+	}{
+		{
+			name: "validation failed",
+			handler: func(ms *mockshandler.MockNewsStorer) http.HandlerFunc {
+				return handler.PostNews(ms)
+			},
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/news", strings.NewReader(`{"author": "code learn"}`))
+				return r.WithContext(requestid.NewContext(r.Context(), "req-1"))
+			},
+			expectedProblem: handler.Problem{
+				Type:      "urn:newsapi:problem:validation_failed",
+				Title:     "Bad Request",
+				Status:    http.StatusBadRequest,
+				Detail:    "the request has invalid fields",
+				Instance:  "/news",
+				Code:      handler.CodeValidationFailed,
+				RequestID: "req-1",
+				Errors: []*handler.FieldError{
+					{Field: "title", Code: handler.FieldCodeRequired, Message: "is empty"},
+					{Field: "content", Code: handler.FieldCodeRequired, Message: "is empty"},
+					{Field: "summary", Code: handler.FieldCodeRequired, Message: "is empty"},
+					{Field: "created_at", Code: handler.FieldCodeInvalid, Message: `is not a valid RFC3339 timestamp: parsing time "" as "2006-01-02T15:04:05Z07:00": cannot parse "" as "2006"`},
+					{Field: "source", Code: handler.FieldCodeRequired, Message: "is empty"},
+					{Field: "tags", Code: handler.FieldCodeRequired, Message: "cannot be empty"},
+				},
+			},
+		},
+		{
+			name: "store error with code",
+			handler: func(ms *mockshandler.MockNewsStorer) http.HandlerFunc {
+				ms.EXPECT().FindByID(gomock.Any(), gomock.Any()).
+					Return(nil, news.NewCustomError(errors.New("no rows"), http.StatusNotFound).WithCode(news.CodeNotFound))
+				return handler.GetNewsByID(ms)
+			},
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/news/3b082d9d-1dc7-4d1f-907e-50d449a03d45", http.NoBody)
+				r.SetPathValue("news_id", "3b082d9d-1dc7-4d1f-907e-50d449a03d45")
+				return r
+			},
+			expectedProblem: handler.Problem{
+				Type:     "urn:newsapi:problem:news_not_found",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "no rows",
+				Instance: "/news/3b082d9d-1dc7-4d1f-907e-50d449a03d45",
+				Code:     news.CodeNotFound,
+			},
+		},
+		{
+			name: "internal error hides detail",
+			handler: func(ms *mockshandler.MockNewsStorer) http.HandlerFunc {
+				ms.EXPECT().DeleteByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+				return handler.DeleteNewsByID(ms)
+			},
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodDelete, "/news/6a3483c7-e28e-442e-b603-b06ff60eeeb4", http.NoBody)
+				r.SetPathValue("news_id", "6a3483c7-e28e-442e-b603-b06ff60eeeb4")
+				return r
+			},
+			expectedProblem: handler.Problem{
+				Type:     "urn:newsapi:problem:internal_error",
+				Title:    "Internal Server Error",
+				Status:   http.StatusInternalServerError,
+				Detail:   "the server failed to process the request",
+				Instance: "/news/6a3483c7-e28e-442e-b603-b06ff60eeeb4",
+				Code:     handler.CodeInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+
+			// Act
+			tc.handler(ms)(w, tc.request())
+
+			// Assert
+			assert.Equal(t, tc.expectedProblem.Status, w.Result().StatusCode)
+			assert.Equal(t, handler.ProblemContentType, w.Result().Header.Get("Content-Type"))
+			var p handler.Problem
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+			assert.Equal(t, tc.expectedProblem, p)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/news"
+)
+
+const (
+	// DefaultPageLimit is used when the request does not set a limit.
+	DefaultPageLimit = 20
+	// MaxPageLimit is the largest page a client can request.
+	MaxPageLimit = 100
+)
+
+// listQueryParams are the query parameters accepted by the news listing.
+var listQueryParams = []string{
+	"limit", "offset", "cursor", "sort",
+	"author", "tag", "tag_match", "source_host",
+	"created_after", "created_before", "updated_since", "status",
+}
+
+// parseListParams builds the listing parameters from the query string.
+func parseListParams(q url.Values) (params news.ListParams, errs error) {
+	errs = unsupportedParams(q, listQueryParams)
+
+	limit, offset, err := parseLimitOffset(q)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	params.Limit, params.Offset = limit, offset
+	if v := q.Get("cursor"); v != "" {
+		c, err := news.DecodeCursor(v)
+		if err != nil {
+			errs = errors.Join(errs, newFieldError("cursor", FieldCodeInvalid, "is invalid: %s", v))
+		}
+		params.Cursor = c
+	}
+	if params.Cursor != nil && params.Offset > 0 {
+		errs = errors.Join(errs, newFieldError("cursor", FieldCodeInvalid, "cannot be used together with offset"))
+	}
+	if v, ok := q["sort"]; ok {
+		sort, err := news.ParseSort(strings.Join(v, ","))
+		if err != nil {
+			errs = errors.Join(errs, newFieldError("sort", FieldCodeInvalid, "is invalid: %s", err))
+		}
+		params.Sort = sort
+	}
+	if params.Cursor != nil && !news.IsDefaultSort(params.Sort) {
+		errs = errors.Join(errs, newFieldError("cursor", FieldCodeInvalid, "can only be used with the default sort order"))
+	}
+
+	filter, err := parseFilter(q)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	params.Filter = filter
+	return params, errs
+}
+
+// searchQueryParams are the query parameters accepted by the news search.
+var searchQueryParams = []string{"q", "limit", "offset"}
+
+// MaxSearchQueryLength is the longest search query accepted.
+const MaxSearchQueryLength = 256
+
+// parseSearchParams builds the search parameters from the query string.
+func parseSearchParams(q url.Values) (params news.SearchParams, errs error) {
+	errs = unsupportedParams(q, searchQueryParams)
+
+	params.Query = strings.TrimSpace(q.Get("q"))
+	if params.Query == "" {
+		errs = errors.Join(errs, newFieldError("q", FieldCodeRequired, "cannot be empty"))
+	}
+	if len(params.Query) > MaxSearchQueryLength {
+		errs = errors.Join(errs, newFieldError("q", FieldCodeTooLong, "cannot be longer than %d characters", MaxSearchQueryLength))
+	}
+
+	limit, offset, err := parseLimitOffset(q)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	params.Limit, params.Offset = limit, offset
+	return params, errs
+}
+
+// parseLimitOffset parses the limit and offset query parameters.
+func parseLimitOffset(q url.Values) (limit, offset int, errs error) {
+	limit = DefaultPageLimit
+	if v := q.Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > MaxPageLimit {
+			errs = errors.Join(errs, newFieldError("limit", FieldCodeInvalid, "must be between 1 and %d: %s", MaxPageLimit, v))
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		var err error
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			errs = errors.Join(errs, newFieldError("offset", FieldCodeInvalid, "must be a non-negative integer: %s", v))
+		}
+	}
+	return limit, offset, errs
+}
+
+// parseFilter builds the listing filter from the query string.
+func parseFilter(q url.Values) (f news.Filter, errs error) {
+	f.Author = q.Get("author")
+	f.SourceHost = q.Get("source_host")
+
+	for _, tag := range q["tag"] {
+		if tag == "" {
+			errs = errors.Join(errs, newFieldError("tag", FieldCodeRequired, "cannot be empty"))
+			continue
+		}
+		f.Tags = append(f.Tags, tag)
+	}
+	if v := q.Get("tag_match"); v != "" {
+		switch news.TagMatch(v) {
+		case news.TagMatchAny, news.TagMatchAll:
+			f.TagMatch = news.TagMatch(v)
+		default:
+			errs = errors.Join(errs, newFieldError("tag_match", FieldCodeInvalid, "must be one of any, all: %s", v))
+		}
+	}
+
+	for _, v := range q["status"] {
+		status, err := news.ParseStatus(v)
+		if err != nil {
+			errs = errors.Join(errs, newFieldError("status", FieldCodeInvalid, "must be one of draft, review, published, archived: %s", v))
+			continue
+		}
+		f.Statuses = append(f.Statuses, status)
+	}
+
+	var err error
+	if f.CreatedAfter, err = parseTimeParam(q, "created_after"); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if f.CreatedBefore, err = parseTimeParam(q, "created_before"); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if f.UpdatedSince, err = parseTimeParam(q, "updated_since"); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if !f.CreatedAfter.IsZero() && !f.CreatedBefore.IsZero() && !f.CreatedAfter.Before(f.CreatedBefore) {
+		errs = errors.Join(errs, newFieldError("created_after", FieldCodeInvalid, "must be before created_before"))
+	}
+	return f, errs
+}
+
+// parseTimeParam parses an optional RFC3339 query parameter.
+func parseTimeParam(q url.Values, key string) (time.Time, error) {
+	v := q.Get(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, newFieldError(key, FieldCodeInvalid, "must be an RFC3339 timestamp: %s", v)
+	}
+	return t, nil
+}
+
+// unsupportedParams returns an error for each query parameter not allowed.
+func unsupportedParams(q url.Values, allowed []string) (errs error) {
+	for k := range q {
+		if !slices.Contains(allowed, k) {
+			errs = errors.Join(errs, newFieldError(k, FieldCodeInvalid, "is not a supported query parameter"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/logger"
+)
+
+// revisionsQueryParams are the query parameters accepted by the revisions
+// listing.
+var revisionsQueryParams = []string{"limit", "offset"}
+
+// revisionDiffQueryParams are the query parameters accepted by the
+// revisions diff.
+var revisionDiffQueryParams = []string{"from", "to"}
+
+// GetRevisions handler.
+func GetRevisions(rs RevisionStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
+		if err != nil {
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
+			return
+		}
+
+		q := r.URL.Query()
+		errs := unsupportedParams(q, revisionsQueryParams)
+		limit, offset, err := parseLimitOffset(q)
+		if errs = errors.Join(errs, err); errs != nil {
+			log.Error("invalid query parameters", "error", errs)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidQuery, errs)
+			return
+		}
+
+		revisions, total, err := rs.FindRevisions(ctx, newsUUID, limit, offset)
+		if err != nil {
+			log.Error("failed to fetch the revisions", "newsId", newsID, "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(RevisionsResponse{Revisions: revisions, TotalHint: total}); err != nil {
+			log.Error("failed to write response", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// GetRevision handler.
+func GetRevision(rs RevisionStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
+		if err != nil {
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
+			return
+		}
+		revision, err := parseRevision("revision", r.PathValue("revision"))
+		if err != nil {
+			log.Error("invalid revision", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidRevision, err)
+			return
+		}
+
+		rev, err := rs.FindRevision(ctx, newsUUID, revision)
+		if err != nil {
+			log.Error("failed to fetch the revision", "newsId", newsID, "revision", revision, "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(rev); err != nil {
+			log.Error("failed to write response", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// GetRevisionDiff handler. It returns the fields changed between the
+// revisions of the from and to query parameters.
+func GetRevisionDiff(rs RevisionStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
+		if err != nil {
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
+			return
+		}
+
+		q := r.URL.Query()
+		errs := unsupportedParams(q, revisionDiffQueryParams)
+		from, err := parseRevision("from", q.Get("from"))
+		errs = errors.Join(errs, err)
+		to, err := parseRevision("to", q.Get("to"))
+		if errs = errors.Join(errs, err); errs != nil {
+			log.Error("invalid query parameters", "error", errs)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidQuery, errs)
+			return
+		}
+
+		changes, err := rs.DiffRevisions(ctx, newsUUID, from, to)
+		if err != nil {
+			log.Error("failed to diff the revisions", "newsId", newsID, "from", from, "to", to, "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(RevisionDiffResponse{From: from, To: to, Changes: changes}); err != nil {
+			log.Error("failed to write response", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RestoreRevision handler. It honours If-Match like the other updates.
+func RestoreRevision(rs RevisionStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
+		if err != nil {
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
+			return
+		}
+		revision, err := parseRevision("revision", r.PathValue("revision"))
+		if err != nil {
+			log.Error("invalid revision", "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidRevision, err)
+			return
+		}
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			log.Error("invalid if-match header", "error", err)
+			writeProblem(w, r, http.StatusPreconditionFailed, CodePreconditionFailed, err)
+			return
+		}
+
+		n, err := rs.RestoreRevision(ctx, newsUUID, revision, version)
+		if err != nil {
+			log.Error("failed to restore the revision", "newsId", newsID, "revision", revision, "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+
+		w.Header().Set("ETag", ETag(n.Version))
+		if err := json.NewEncoder(w).Encode(n); err != nil {
+			log.Error("failed to encode", "newsId", newsID, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// parseRevision parses a required revision number.
+func parseRevision(field, v string) (int, error) {
+	if v == "" {
+		return 0, newFieldError(field, FieldCodeRequired, "is empty")
+	}
+	revision, err := strconv.Atoi(v)
+	if err != nil || revision < 1 {
+		return 0, newFieldError(field, FieldCodeInvalid, "must be a positive integer: %s", v)
+	}
+	return revision, nil
+}
@@ -0,0 +1,260 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/handler"
+	mockshandler "github.com/prashsamosa/newsapi/internal/handler/mocks"
+	"github.com/prashsamosa/newsapi/internal/news"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_GetRevisions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		newsID         string
+		query          string
+		setup          func(testing.TB) *mockshandler.MockRevisionStorer
+		expectedStatus int
+	}{
+		{
+			name:   "invalid news id",
+			newsID: "invalid-uuid",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				return mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "unsupported query parameter",
+			newsID: uuid.NewString(),
+			query:  "?sort=revision",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				return mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "not found",
+			newsID: uuid.NewString(),
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+				ms.EXPECT().FindRevisions(gomock.Any(), gomock.Any(), handler.DefaultPageLimit, 0).
+					Return(nil, 0, news.NewCustomError(errors.New("not found"), http.StatusNotFound))
+				return ms
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "success",
+			newsID: uuid.NewString(),
+			query:  "?limit=5&offset=10",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+				ms.EXPECT().FindRevisions(gomock.Any(), gomock.Any(), 5, 10).Return([]*news.Revision{{Revision: 1}}, 1, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/"+tc.query, http.NoBody)
+			r.SetPathValue("news_id", tc.newsID)
+
+			// Act
+			handler.GetRevisions(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func Test_GetRevision(t *testing.T) {
+	testCases := []struct {
+		name           string
+		revision       string
+		setup          func(testing.TB) *mockshandler.MockRevisionStorer
+		expectedStatus int
+	}{
+		{
+			name:     "invalid revision",
+			revision: "0",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				return mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "not found",
+			revision: "3",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+				ms.EXPECT().FindRevision(gomock.Any(), gomock.Any(), 3).
+					Return(nil, news.NewCustomError(errors.New("not found"), http.StatusNotFound))
+				return ms
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "success",
+			revision: "2",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+				ms.EXPECT().FindRevision(gomock.Any(), gomock.Any(), 2).Return(&news.Revision{Revision: 2}, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			r.SetPathValue("news_id", uuid.NewString())
+			r.SetPathValue("revision", tc.revision)
+
+			// Act
+			handler.GetRevision(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func Test_GetRevisionDiff(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		setup          func(testing.TB) *mockshandler.MockRevisionStorer
+		expectedStatus int
+	}{
+		{
+			name:  "missing to",
+			query: "?from=1",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				return mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid from",
+			query: "?from=first&to=2",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				return mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "success",
+			query: "?from=1&to=3",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+				ms.EXPECT().DiffRevisions(gomock.Any(), gomock.Any(), 1, 3).
+					Return(map[string]news.Change{"title": {Old: "old", New: "new"}}, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/"+tc.query, http.NoBody)
+			r.SetPathValue("news_id", uuid.NewString())
+
+			// Act
+			handler.GetRevisionDiff(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func Test_RestoreRevision(t *testing.T) {
+	testCases := []struct {
+		name           string
+		ifMatch        string
+		setup          func(testing.TB) *mockshandler.MockRevisionStorer
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name:    "invalid if-match",
+			ifMatch: `W/"2"`,
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				return mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "stale version",
+			ifMatch: `"2"`,
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+				ms.EXPECT().RestoreRevision(gomock.Any(), gomock.Any(), 1, 2).
+					Return(nil, news.NewCustomError(news.ErrVersionMismatch, http.StatusPreconditionFailed))
+				return ms
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "success",
+			setup: func(tb testing.TB) *mockshandler.MockRevisionStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockRevisionStorer(gomock.NewController(tb))
+				ms.EXPECT().RestoreRevision(gomock.Any(), gomock.Any(), 1, 0).Return(&news.Record{Version: 5}, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"5"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+			r.SetPathValue("news_id", uuid.NewString())
+			r.SetPathValue("revision", "1")
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			// Act
+			handler.RestoreRevision(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			assert.Equal(t, tc.expectedETag, w.Result().Header.Get("ETag"))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/logger"
+	"github.com/prashsamosa/newsapi/internal/news"
+)
+
+// EditorScope is the scope allowing to see the news that are not published.
+const EditorScope = auth.ScopeNewsWrite
+
+// errNewsNotFound is returned for the news hidden from the caller.
+var errNewsNotFound = errors.New("news not found")
+
+// errUnpublishedForbidden is returned when a caller without the editor
+// scope lists the news that are not published.
+var errUnpublishedForbidden = errors.New("only editors can list news that are not published")
+
+// TransitionNews handler. It moves the news to the status, honouring
+// If-Match like the other updates.
+func TransitionNews(ss StatusStorer, to news.Status) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
+		if err != nil {
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
+			return
+		}
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			log.Error("invalid if-match header", "error", err)
+			writeProblem(w, r, http.StatusPreconditionFailed, CodePreconditionFailed, err)
+			return
+		}
+
+		n, err := ss.Transition(ctx, newsUUID, to, version)
+		if err != nil {
+			log.Error("failed to change the news status", "newsId", newsID, "status", to, "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+
+		w.Header().Set("ETag", ETag(n.Version))
+		if err := json.NewEncoder(w).Encode(n); err != nil {
+			log.Error("failed to encode", "newsId", newsID, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// canSeeUnpublished reports whether the caller can see the news that are
+// not published. Without a principal authentication is disabled and every
+// caller is trusted.
+func canSeeUnpublished(ctx context.Context) bool {
+	p, ok := auth.FromContext(ctx)
+	return !ok || p.HasScope(EditorScope)
+}
+
+// visibleStatuses returns the statuses the caller can list among the
+// requested ones. Callers without the editor scope only see the published
+// news.
+func visibleStatuses(ctx context.Context, requested []news.Status) ([]news.Status, error) {
+	if canSeeUnpublished(ctx) {
+		return requested, nil
+	}
+	for _, s := range requested {
+		if s != news.StatusPublished {
+			return nil, errUnpublishedForbidden
+		}
+	}
+	return []news.Status{news.StatusPublished}, nil
+}
@@ -0,0 +1,226 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/handler"
+	mockshandler "github.com/prashsamosa/newsapi/internal/handler/mocks"
+	"github.com/prashsamosa/newsapi/internal/news"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	reader = &auth.Principal{Subject: "reader", Scopes: []auth.Scope{auth.ScopeNewsRead}}
+	editor = &auth.Principal{Subject: "editor", Scopes: []auth.Scope{auth.ScopeNewsWrite}}
+)
+
+func Test_TransitionNews(t *testing.T) {
+	testCases := []struct {
+		name           string
+		newsID         string
+		ifMatch        string
+		setup          func(testing.TB) *mockshandler.MockStatusStorer
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name:   "invalid news id",
+			newsID: "invalid-uuid",
+			setup: func(tb testing.TB) *mockshandler.MockStatusStorer {
+				tb.Helper()
+				return mockshandler.NewMockStatusStorer(gomock.NewController(tb))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid transition",
+			newsID: uuid.NewString(),
+			setup: func(tb testing.TB) *mockshandler.MockStatusStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockStatusStorer(gomock.NewController(tb))
+				ms.EXPECT().Transition(gomock.Any(), gomock.Any(), news.StatusPublished, 0).
+					Return(nil, news.NewCustomError(news.ErrInvalidTransition, http.StatusConflict))
+				return ms
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:    "success",
+			newsID:  uuid.NewString(),
+			ifMatch: `"3"`,
+			setup: func(tb testing.TB) *mockshandler.MockStatusStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockStatusStorer(gomock.NewController(tb))
+				ms.EXPECT().Transition(gomock.Any(), gomock.Any(), news.StatusPublished, 3).
+					Return(&news.Record{Status: news.StatusPublished, Version: 4}, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"4"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+			r.SetPathValue("news_id", tc.newsID)
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			// Act
+			handler.TransitionNews(tc.setup(t), news.StatusPublished)(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			assert.Equal(t, tc.expectedETag, w.Result().Header.Get("ETag"))
+		})
+	}
+}
+
+func Test_GetNewsByID_Status(t *testing.T) {
+	testCases := []struct {
+		name           string
+		principal      *auth.Principal
+		status         news.Status
+		publishAt      time.Time
+		expectedStatus int
+	}{
+		{
+			name:           "published news for a reader",
+			principal:      reader,
+			status:         news.StatusPublished,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "draft for a reader",
+			principal:      reader,
+			status:         news.StatusDraft,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "scheduled news for a reader",
+			principal:      reader,
+			status:         news.StatusPublished,
+			publishAt:      time.Now().Add(time.Hour),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "draft for an editor",
+			principal:      editor,
+			status:         news.StatusDraft,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			ms.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(&news.Record{Status: tc.status, PublishAt: tc.publishAt}, nil)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			r = r.WithContext(auth.NewContext(r.Context(), tc.principal))
+			r.SetPathValue("news_id", uuid.NewString())
+
+			// Act
+			handler.GetNewsByID(ms)(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func Test_GetAllNews_Status(t *testing.T) {
+	testCases := []struct {
+		name             string
+		principal        *auth.Principal
+		query            string
+		expectedStatuses []news.Status
+		expectedStatus   int
+	}{
+		{
+			name:             "reader only sees published news",
+			principal:        reader,
+			expectedStatuses: []news.Status{news.StatusPublished},
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:           "reader cannot list drafts",
+			principal:      reader,
+			query:          "?status=draft",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "editor sees every status",
+			principal:      editor,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "editor lists drafts",
+			principal:        editor,
+			query:            "?status=draft&status=review",
+			expectedStatuses: []news.Status{news.StatusDraft, news.StatusReview},
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:           "invalid status",
+			principal:      editor,
+			query:          "?status=deleted",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+			if tc.expectedStatus == http.StatusOK {
+				ms.EXPECT().FindPage(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, params news.ListParams) (*news.Page, error) {
+						assert.Equal(t, tc.expectedStatuses, params.Filter.Statuses)
+						assert.Equal(t, tc.principal == reader, !params.Filter.LiveAt.IsZero())
+						return &news.Page{}, nil
+					})
+			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/news"+tc.query, http.NoBody)
+			r = r.WithContext(auth.NewContext(r.Context(), tc.principal))
+
+			// Act
+			handler.GetAllNews(ms)(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func Test_SearchNews_Status(t *testing.T) {
+	// Arrange
+	ms := mockshandler.NewMockNewsStorer(gomock.NewController(t))
+	ms.EXPECT().Search(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, params news.SearchParams) ([]*news.SearchResult, error) {
+			assert.Equal(t, []news.Status{news.StatusPublished}, params.Statuses)
+			return nil, errors.New("db error")
+		})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/news/search?q=news", http.NoBody)
+	r = r.WithContext(auth.NewContext(r.Context(), reader))
+
+	// Act
+	handler.SearchNews(ms)(w, r)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/logger"
+)
+
+// trashQueryParams are the query parameters accepted by the trash listing.
+var trashQueryParams = []string{"limit", "offset"}
+
+// GetTrash handler.
+func GetTrash(ts TrashStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+
+		q := r.URL.Query()
+		errs := unsupportedParams(q, trashQueryParams)
+		limit, offset, err := parseLimitOffset(q)
+		if errs = errors.Join(errs, err); errs != nil {
+			log.Error("invalid query parameters", "error", errs)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidQuery, errs)
+			return
+		}
+
+		page, err := ts.FindTrash(ctx, limit, offset)
+		if err != nil {
+			log.Error("failed to fetch the trash", "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(NewAllNewsResponse(page)); err != nil {
+			log.Error("failed to write response", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RestoreNewsByID handler.
+func RestoreNewsByID(ts TrashStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		log.Info("request received")
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
+		if err != nil {
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
+			return
+		}
+
+		n, err := ts.RestoreByID(ctx, newsUUID)
+		if err != nil {
+			log.Error("failed to restore news", "newsId", newsID, "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+
+		w.Header().Set("ETag", ETag(n.Version))
+		if err := json.NewEncoder(w).Encode(n); err != nil {
+			log.Error("failed to encode", "newsId", newsID, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// PurgeNewsByID handler.
+func PurgeNewsByID(ts TrashStorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		log := logger.FromContext(ctx)
+		newsID := r.PathValue("news_id")
+		newsUUID, err := uuid.Parse(newsID)
+		if err != nil {
+			log.Error("news id not a valid uuid", "newsId", newsID, "error", err)
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidNewsID, err)
+			return
+		}
+
+		if err := ts.PurgeByID(ctx, newsUUID); err != nil {
+			log.Error("failed to purge news", "newsId", newsID, "error", err)
+			writeStoreError(w, r, err)
+			return
+		}
+		log.Info("news purged", "newsId", newsID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// WithPurge routes a delete request to the purge handler when the purge
+// query parameter is true.
+func WithPurge(deleteHandler, purgeHandler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v := r.URL.Query().Get("purge")
+		if v == "" {
+			deleteHandler.ServeHTTP(w, r)
+			return
+		}
+		purge, err := strconv.ParseBool(v)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidQuery, newFieldError("purge", FieldCodeInvalid, "must be a boolean: %s", v))
+			return
+		}
+		if purge {
+			purgeHandler.ServeHTTP(w, r)
+			return
+		}
+		deleteHandler.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,224 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/handler"
+	mockshandler "github.com/prashsamosa/newsapi/internal/handler/mocks"
+	"github.com/prashsamosa/newsapi/internal/news"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_GetTrash(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		setup          func(testing.TB) *mockshandler.MockTrashStorer
+		expectedStatus int
+	}{
+		{
+			name:  "unsupported query parameter",
+			query: "?sort=title",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				return mockshandler.NewMockTrashStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid limit",
+			query: "?limit=0",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				return mockshandler.NewMockTrashStorer(gomock.NewController(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "db error",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockTrashStorer(gomock.NewController(t))
+				ms.EXPECT().FindTrash(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+				return ms
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:  "success",
+			query: "?limit=5&offset=10",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockTrashStorer(gomock.NewController(t))
+				ms.EXPECT().FindTrash(gomock.Any(), 5, 10).Return(&news.Page{}, nil)
+				return ms
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/news/trash"+tc.query, http.NoBody)
+
+			// Act
+			handler.GetTrash(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func Test_RestoreNewsByID(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(testing.TB) *mockshandler.MockTrashStorer
+		newsID         string
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name: "invalid news id",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				return mockshandler.NewMockTrashStorer(gomock.NewController(t))
+			},
+			newsID:         "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not in trash",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockTrashStorer(gomock.NewController(t))
+				ms.EXPECT().RestoreByID(gomock.Any(), gomock.Any()).
+					Return(nil, news.NewCustomError(errors.New("not found"), http.StatusNotFound))
+				return ms
+			},
+			newsID:         uuid.NewString(),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "success",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockTrashStorer(gomock.NewController(t))
+				ms.EXPECT().RestoreByID(gomock.Any(), gomock.Any()).Return(&news.Record{Version: 4}, nil)
+				return ms
+			},
+			newsID:         uuid.NewString(),
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"4"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+			r.SetPathValue("news_id", tc.newsID)
+
+			// Act
+			handler.RestoreNewsByID(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+			assert.Equal(t, tc.expectedETag, w.Result().Header.Get("ETag"))
+		})
+	}
+}
+
+func Test_PurgeNewsByID(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(testing.TB) *mockshandler.MockTrashStorer
+		newsID         string
+		expectedStatus int
+	}{
+		{
+			name: "invalid news id",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				return mockshandler.NewMockTrashStorer(gomock.NewController(t))
+			},
+			newsID:         "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockTrashStorer(gomock.NewController(t))
+				ms.EXPECT().PurgeByID(gomock.Any(), gomock.Any()).
+					Return(news.NewCustomError(errors.New("not found"), http.StatusNotFound))
+				return ms
+			},
+			newsID:         uuid.NewString(),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "success",
+			setup: func(tb testing.TB) *mockshandler.MockTrashStorer {
+				tb.Helper()
+				ms := mockshandler.NewMockTrashStorer(gomock.NewController(t))
+				ms.EXPECT().PurgeByID(gomock.Any(), gomock.Any()).Return(nil)
+				return ms
+			},
+			newsID:         uuid.NewString(),
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/", http.NoBody)
+			r.SetPathValue("news_id", tc.newsID)
+
+			// Act
+			handler.PurgeNewsByID(tc.setup(t))(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func Test_WithPurge(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "no purge", query: "", expectedStatus: http.StatusNoContent},
+		{name: "purge false", query: "?purge=false", expectedStatus: http.StatusNoContent},
+		{name: "purge true", query: "?purge=true", expectedStatus: http.StatusAccepted},
+		{name: "invalid purge", query: "?purge=maybe", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/"+tc.query, http.NoBody)
+			deleteHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+			purgeHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) })
+
+			// Act
+			handler.WithPurge(deleteHandler, purgeHandler)(w, r)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/prashsamosa/newsapi/internal/news"
+)
+
+// Limits of the news request fields.
+const (
+	MaxAuthorLength  = 200
+	MaxTitleLength   = 300
+	MaxSummaryLength = 2000
+	MaxContentLength = 100_000
+	MaxSourceLength  = 2048
+	MaxTags          = 20
+	MaxTagLength     = 50
+	// MaxCreatedAtSkew is how far in the future created_at can be, to
+	// allow for clock skew between the clients and the server.
+	MaxCreatedAtSkew = time.Hour
+)
+
+// Codes of the field validation errors.
+const (
+	FieldCodeRequired  = "required"
+	FieldCodeTooLong   = "too_long"
+	FieldCodeTooMany   = "too_many"
+	FieldCodeInvalid   = "invalid"
+	FieldCodeDuplicate = "duplicate"
+	FieldCodeInFuture  = "in_future"
+)
+
+// tagPattern is the format of a tag: letters, digits, spaces, hyphens and
+// underscores, starting with a letter or a digit.
+var tagPattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} _-]*$`)
+
+// FieldError is a validation error of a single request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// newFieldError returns a field error with a formatted message.
+func newFieldError(field, code, format string, args ...any) *FieldError {
+	return &FieldError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// Validate the incoming request.
+func (n *NewsPostReqBody) Validate() (record *news.Record, errs error) {
+	errs = errors.Join(errs, validateText("author", n.Author, MaxAuthorLength))
+	errs = errors.Join(errs, validateText("title", n.Title, MaxTitleLength))
+	errs = errors.Join(errs, validateText("content", n.Content, MaxContentLength))
+	errs = errors.Join(errs, validateText("summary", n.Summary, MaxSummaryLength))
+
+	t, err := time.Parse(time.RFC3339, n.CreatedAt)
+	if err != nil {
+		errs = errors.Join(errs, newFieldError("created_at", FieldCodeInvalid, "is not a valid RFC3339 timestamp: %s", err))
+	} else if t.After(time.Now().Add(MaxCreatedAtSkew)) {
+		errs = errors.Join(errs, newFieldError("created_at", FieldCodeInFuture, "cannot be in the future: %s", n.CreatedAt))
+	}
+
+	source, err := validateSource(n.Source)
+	errs = errors.Join(errs, err)
+	errs = errors.Join(errs, validateTags(n.Tags))
+
+	publishAt, expireAt, err := validateSchedule(n.PublishAt, n.ExpireAt)
+	errs = errors.Join(errs, err)
+
+	if errs != nil {
+		return record, errs
+	}
+	return &news.Record{
+		ID:        n.ID,
+		Author:    n.Author,
+		Title:     n.Title,
+		Content:   n.Content,
+		Summary:   n.Summary,
+		CreatedAt: t,
+		Source:    source.String(),
+		Tags:      n.Tags,
+		PublishAt: publishAt,
+		ExpireAt:  expireAt,
+	}, nil
+}
+
+// validateSchedule parses the optional publication window, which must end
+// after it starts.
+func validateSchedule(publish, expire string) (publishAt, expireAt time.Time, errs error) {
+	var err error
+	if publish != "" {
+		if publishAt, err = time.Parse(time.RFC3339, publish); err != nil {
+			errs = errors.Join(errs, newFieldError("publish_at", FieldCodeInvalid, "is not a valid RFC3339 timestamp: %s", err))
+		}
+	}
+	if expire != "" {
+		if expireAt, err = time.Parse(time.RFC3339, expire); err != nil {
+			errs = errors.Join(errs, newFieldError("expire_at", FieldCodeInvalid, "is not a valid RFC3339 timestamp: %s", err))
+		}
+	}
+	if !publishAt.IsZero() && !expireAt.IsZero() && !expireAt.After(publishAt) {
+		errs = errors.Join(errs, newFieldError("expire_at", FieldCodeInvalid, "must be after publish_at"))
+	}
+	return publishAt, expireAt, errs
+}
+
+// validateText checks a required text field and its length in characters.
+func validateText(field, value string, maxLength int) error {
+	if strings.TrimSpace(value) == "" {
+		return newFieldError(field, FieldCodeRequired, "is empty")
+	}
+	if utf8.RuneCountInString(value) > maxLength {
+		return newFieldError(field, FieldCodeTooLong, "cannot be longer than %d characters", maxLength)
+	}
+	return nil
+}
+
+// validateSource checks the source is an absolute http or https url.
+func validateSource(source string) (*url.URL, error) {
+	if source == "" {
+		return nil, newFieldError("source", FieldCodeRequired, "is empty")
+	}
+	if len(source) > MaxSourceLength {
+		return nil, newFieldError("source", FieldCodeTooLong, "cannot be longer than %d characters", MaxSourceLength)
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, newFieldError("source", FieldCodeInvalid, "is not a valid url: %s", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, newFieldError("source", FieldCodeInvalid, "must be an http or https url: %s", source)
+	}
+	if u.Host == "" || u.Hostname() == "" {
+		return nil, newFieldError("source", FieldCodeInvalid, "must be an absolute url with a host: %s", source)
+	}
+	return u, nil
+}
+
+// validateTags checks the number, format and uniqueness of the tags.
+func validateTags(tags []string) (errs error) {
+	if len(tags) == 0 {
+		return newFieldError("tags", FieldCodeRequired, "cannot be empty")
+	}
+	if len(tags) > MaxTags {
+		errs = errors.Join(errs, newFieldError("tags", FieldCodeTooMany, "cannot have more than %d tags", MaxTags))
+	}
+	seen := make(map[string]int, len(tags))
+	for i, tag := range tags {
+		field := fmt.Sprintf("tags[%d]", i)
+		switch {
+		case utf8.RuneCountInString(tag) > MaxTagLength:
+			errs = errors.Join(errs, newFieldError(field, FieldCodeTooLong, "cannot be longer than %d characters", MaxTagLength))
+		case !tagPattern.MatchString(tag):
+			errs = errors.Join(errs, newFieldError(field, FieldCodeInvalid,
+				"must start with a letter or digit and contain only letters, digits, spaces, hyphens and underscores: %q", tag))
+		}
+		key := strings.ToLower(tag)
+		if j, ok := seen[key]; ok {
+			errs = errors.Join(errs, newFieldError(field, FieldCodeDuplicate, "duplicates tags[%d]: %q", j, tag))
+			continue
+		}
+		seen[key] = i
+	}
+	return errs
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+// DBCheck checks the database accepts connections.
+func DBCheck(db *bun.DB) Check {
+	return Check{
+		Name: "database",
+		Check: func(ctx context.Context) error {
+			return db.PingContext(ctx)
+		},
+	}
+}
+
+// MigrationsCheck checks every migration has been applied to the database.
+func MigrationsCheck(m *migrate.Migrator) Check {
+	return Check{
+		Name: "migrations",
+		Check: func(ctx context.Context) error {
+			ms, err := m.MigrationsWithStatus(ctx)
+			if err != nil {
+				return fmt.Errorf("migrations status: %w", err)
+			}
+			if unapplied := ms.Unapplied(); len(unapplied) > 0 {
+				names := make([]string, 0, len(unapplied))
+				for _, m := range unapplied {
+					names = append(names, m.Name)
+				}
+				return fmt.Errorf("%d migrations not applied: %s", len(unapplied), strings.Join(names, ", "))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,147 @@
+// Package health serves the liveness, readiness and startup probes of the
+// server.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/logger"
+)
+
+// DefaultTimeout bounds the time taken by all the checks of a probe.
+const DefaultTimeout = 2 * time.Second
+
+// Statuses of the probes and of their checks.
+const (
+	StatusOK           = "ok"
+	StatusFailed       = "failed"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check is a dependency the server needs to serve the requests.
+type Check struct {
+	Name string
+	// Check returns an error when the dependency is not available.
+	Check func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a check.
+type CheckResult struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Report is the response of the readiness and startup probes.
+type Report struct {
+	Status string                  `json:"status"`
+	Checks map[string]*CheckResult `json:"checks,omitempty"`
+}
+
+// Health runs the checks of the probes.
+type Health struct {
+	checks       []Check
+	timeout      time.Duration
+	started      atomic.Bool
+	shuttingDown atomic.Bool
+}
+
+// New returns the probes running the checks within the timeout.
+func New(timeout time.Duration, checks ...Check) *Health {
+	return &Health{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// Shutdown makes the readiness probe fail, so that the server stops getting
+// new traffic while it shuts down.
+func (h *Health) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Live handler, successful as long as the process serves requests.
+func (h *Health) Live() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, r, http.StatusOK, &Report{Status: StatusOK})
+	}
+}
+
+// Ready handler, successful when all the checks pass and the server is not
+// shutting down.
+func (h *Health) Ready() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.shuttingDown.Load() {
+			writeReport(w, r, http.StatusServiceUnavailable, &Report{Status: StatusShuttingDown})
+			return
+		}
+		report := h.run(r.Context())
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		} else {
+			h.started.Store(true)
+		}
+		writeReport(w, r, status, report)
+	}
+}
+
+// Startup handler, successful once the checks passed for the first time.
+func (h *Health) Startup() http.HandlerFunc {
+	ready := h.Ready()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.started.Load() {
+			writeReport(w, r, http.StatusOK, &Report{Status: StatusOK})
+			return
+		}
+		ready(w, r)
+	}
+}
+
+// run runs the checks concurrently.
+func (h *Health) run(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	report := &Report{Status: StatusOK, Checks: make(map[string]*CheckResult, len(h.checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := c.Check(ctx)
+			res := &CheckResult{Status: StatusOK, Latency: time.Since(start).String()}
+			if err != nil {
+				res.Status, res.Error = StatusFailed, err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.Name] = res
+			if err != nil {
+				report.Status = StatusFailed
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// writeReport writes the report of a probe.
+func writeReport(w http.ResponseWriter, r *http.Request, status int, report *Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logger.FromContext(r.Context()).Error("failed to write health report", "error", err)
+	}
+}
@@ -0,0 +1,146 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// check returns a check failing with err.
+func check(name string, err error) health.Check {
+	return health.Check{
+		Name:  name,
+		Check: func(context.Context) error { return err },
+	}
+}
+
+// probe calls the handler and returns the status code and the report.
+func probe(t *testing.T, h http.HandlerFunc) (int, health.Report) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	var report health.Report
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+	return w.Code, report
+}
+
+func TestHealth_Live(t *testing.T) {
+	// Arrange
+	h := health.New(time.Second, check("database", errors.New("connection refused")))
+
+	// Act
+	code, report := probe(t, h.Live())
+
+	// Assert
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+}
+
+func TestHealth_Ready(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checks         []health.Check
+		shutdown       bool
+		expectedCode   int
+		expectedStatus string
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "all checks pass",
+			checks:         []health.Check{check("database", nil), check("migrations", nil)},
+			expectedCode:   http.StatusOK,
+			expectedStatus: health.StatusOK,
+			expectedChecks: map[string]string{"database": health.StatusOK, "migrations": health.StatusOK},
+		},
+		{
+			name:           "a check fails",
+			checks:         []health.Check{check("database", nil), check("migrations", errors.New("1 migrations not applied"))},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: health.StatusFailed,
+			expectedChecks: map[string]string{"database": health.StatusOK, "migrations": health.StatusFailed},
+		},
+		{
+			name:           "shutting down",
+			checks:         []health.Check{check("database", nil)},
+			shutdown:       true,
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: health.StatusShuttingDown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			h := health.New(time.Second, tc.checks...)
+			if tc.shutdown {
+				h.Shutdown()
+			}
+
+			// Act
+			code, report := probe(t, h.Ready())
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, code)
+			assert.Equal(t, tc.expectedStatus, report.Status)
+			checks := make(map[string]string)
+			for name, res := range report.Checks {
+				checks[name] = res.Status
+			}
+			if tc.expectedChecks == nil {
+				assert.Empty(t, checks)
+			} else {
+				assert.Equal(t, tc.expectedChecks, checks)
+			}
+		})
+	}
+}
+
+func TestHealth_Ready_Timeout(t *testing.T) {
+	// Arrange
+	slow := health.Check{
+		Name: "database",
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	h := health.New(10*time.Millisecond, slow)
+
+	// Act
+	code, report := probe(t, h.Ready())
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+}
+
+func TestHealth_Startup(t *testing.T) {
+	// Arrange
+	var err error = errors.New("connection refused")
+	h := health.New(time.Second, health.Check{
+		Name:  "database",
+		Check: func(context.Context) error { return err },
+	})
+
+	// Act & Assert
+	code, _ := probe(t, h.Startup())
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	err = nil
+	code, _ = probe(t, h.Startup())
+	assert.Equal(t, http.StatusOK, code)
+
+	// Once started, the startup probe no longer runs the checks.
+	err = errors.New("connection refused")
+	code, report := probe(t, h.Startup())
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, report.Checks)
+}
@@ -0,0 +1,28 @@
+package idempotency
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Record used to represent an idempotency key in the database. A record
+// without a status code belongs to a request still in progress.
+type Record struct {
+	bun.BaseModel `bun:"table:idempotency_keys"`
+	Key           string      `bun:"key,pk"`
+	Fingerprint   string      `bun:"fingerprint,notnull"`
+	StatusCode    int         `bun:"status_code,nullzero"`
+	Header        http.Header `bun:"header,type:jsonb,nullzero"`
+	Body          []byte      `bun:"body,type:bytea"`
+	CreatedAt     time.Time   `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	// LockedUntil is the end of the lease of the request in progress, after
+	// which another request can take the key over.
+	LockedUntil time.Time `bun:"locked_until,nullzero"`
+}
+
+// Completed reports whether the response of the request was stored.
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
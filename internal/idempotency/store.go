@@ -0,0 +1,147 @@
+// Package idempotency stores the responses of requests sent with an
+// Idempotency-Key header, so that retries can be answered with the original
+// response.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// DefaultTTL is how long a key is kept when no TTL is configured.
+	DefaultTTL = 24 * time.Hour
+	// DefaultLease is how long a request in progress holds its key when no
+	// lease is configured.
+	DefaultLease = time.Minute
+)
+
+// ErrNotFound is returned when the key does not exist.
+var ErrNotFound = errors.New("idempotency key not found")
+
+// Store is a wrapper around bun.DB.
+type Store struct {
+	db    bun.IDB
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// NewStore returns an instance of idempotency key store. Keys expire after
+// the ttl, DefaultTTL when not positive, and the requests in progress hold
+// their key for the lease, DefaultLease when not positive.
+func NewStore(db bun.IDB, ttl, lease time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if lease <= 0 {
+		lease = DefaultLease
+	}
+	return &Store{
+		db:    db,
+		ttl:   ttl,
+		lease: lease,
+	}
+}
+
+// TTL returns how long the keys are kept.
+func (s Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// Reserve claims the key for a new request with the given fingerprint. An
+// expired key, or a key whose request in progress outlived its lease, is
+// claimed again. When the key is already taken, the stored record is
+// returned and reserved is false.
+func (s Store) Reserve(ctx context.Context, key, fingerprint string) (rec *Record, reserved bool, err error) {
+	// The lease identifies the reservation, truncated to the precision of
+	// the database.
+	now := time.Now().Truncate(time.Microsecond)
+	rec = &Record{Key: key, Fingerprint: fingerprint, CreatedAt: now, LockedUntil: now.Add(s.lease)}
+	r, err := s.db.NewInsert().
+		Model(rec).
+		On("CONFLICT (key) DO UPDATE").
+		Set("fingerprint = EXCLUDED.fingerprint").
+		Set("status_code = NULL").
+		Set("header = NULL").
+		Set("body = NULL").
+		Set("created_at = EXCLUDED.created_at").
+		Set("locked_until = EXCLUDED.locked_until").
+		Where("?TableAlias.created_at < ? OR (?TableAlias.status_code IS NULL AND ?TableAlias.locked_until < ?)",
+			now.Add(-s.ttl), now).
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	rowsAffected, err := r.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	if rowsAffected > 0 {
+		return rec, true, nil
+	}
+
+	rec = new(Record)
+	if err := s.db.NewSelect().Model(rec).Where("key = ?", key).Scan(ctx); err != nil {
+		return nil, false, fmt.Errorf("find idempotency key: %w", err)
+	}
+	return rec, false, nil
+}
+
+// Complete stores the response of the request holding the key. It returns
+// ErrNotFound when the key was taken over by another request.
+func (s Store) Complete(ctx context.Context, rec *Record) error {
+	r, err := s.db.NewUpdate().
+		Model(rec).
+		Column("status_code", "header", "body").
+		WherePK().
+		Where("status_code IS NULL").
+		Where("locked_until = ?", rec.LockedUntil).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	rowsAffected, err := r.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Release frees a key whose request did not complete, so that it can be
+// retried. A key taken over by another request is left alone.
+func (s Store) Release(ctx context.Context, rec *Record) error {
+	if _, err := s.db.NewDelete().
+		Model((*Record)(nil)).
+		Where("key = ?", rec.Key).
+		Where("status_code IS NULL").
+		Where("locked_until = ?", rec.LockedUntil).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Purge deletes the keys created before the given time and returns how many
+// were deleted.
+func (s Store) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r, err := s.db.NewDelete().
+		Model((*Record)(nil)).
+		Where("created_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("purge idempotency keys: %w", err)
+	}
+	n, err := r.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("purge idempotency keys: %w", err)
+	}
+	return n, nil
+}
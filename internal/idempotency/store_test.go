@@ -0,0 +1,170 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/idempotency"
+	"github.com/prashsamosa/newsapi/internal/postgres/postgrestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+var db *bun.DB
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	pdb, cf, err := postgrestest.NewDB(ctx, "testdata/sql/store.sql")
+	if errors.Is(err, postgrestest.ErrUnavailable) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(m.Run())
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	db = pdb
+	code := m.Run()
+
+	if err := cf(ctx); err != nil {
+		panic(err)
+	}
+
+	os.Exit(code)
+}
+
+func TestStore_Reserve(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	testCases := []struct {
+		name               string
+		key                string
+		fingerprint        string
+		expectedReserved   bool
+		expectedStatusCode int
+	}{
+		{
+			name:             "new key",
+			key:              "new-key",
+			fingerprint:      "fingerprint",
+			expectedReserved: true,
+		},
+		{
+			name:        "key in progress",
+			key:         "new-key",
+			fingerprint: "other-fingerprint",
+		},
+		{
+			name:               "completed key",
+			key:                "completed-key",
+			fingerprint:        "fingerprint",
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:             "expired key",
+			key:              "expired-key",
+			fingerprint:      "other-fingerprint",
+			expectedReserved: true,
+		},
+		{
+			name:             "expired lease",
+			key:              "abandoned-key",
+			fingerprint:      "fingerprint",
+			expectedReserved: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := idempotency.NewStore(db, time.Hour, time.Minute)
+
+			rec, reserved, err := s.Reserve(context.Background(), tc.key, tc.fingerprint)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedReserved, reserved)
+			assert.Equal(t, tc.key, rec.Key)
+			assert.Equal(t, tc.expectedStatusCode, rec.StatusCode)
+			if tc.expectedReserved {
+				assert.Equal(t, tc.fingerprint, rec.Fingerprint)
+			}
+		})
+	}
+}
+
+func TestStore_Complete(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := idempotency.NewStore(db, time.Hour, time.Minute)
+	ctx := context.Background()
+	rec, reserved, err := s.Reserve(ctx, "complete-key", "fingerprint")
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	rec.StatusCode = http.StatusCreated
+	rec.Header = http.Header{"Location": {"/news/3b082d9d-1dc7-4d1f-907e-50d449a03d45"}}
+	rec.Body = []byte(`{"ID":"3b082d9d-1dc7-4d1f-907e-50d449a03d45"}`)
+	require.NoError(t, s.Complete(ctx, rec))
+
+	got, reserved, err := s.Reserve(ctx, "complete-key", "fingerprint")
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.True(t, got.Completed())
+	assert.Equal(t, rec.Header, got.Header)
+	assert.Equal(t, rec.Body, got.Body)
+
+	err = s.Complete(ctx, &idempotency.Record{Key: "unknown-key", StatusCode: http.StatusCreated})
+	assert.ErrorIs(t, err, idempotency.ErrNotFound)
+}
+
+func TestStore_Release(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := idempotency.NewStore(db, time.Hour, time.Minute)
+	ctx := context.Background()
+	rec, reserved, err := s.Reserve(ctx, "release-key", "fingerprint")
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	require.NoError(t, s.Release(ctx, rec))
+
+	_, reserved, err = s.Reserve(ctx, "release-key", "other-fingerprint")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
+
+func TestStore_Lease(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := idempotency.NewStore(db, time.Hour, time.Millisecond)
+	ctx := context.Background()
+	stale, reserved, err := s.Reserve(ctx, "lease-key", "fingerprint")
+	require.NoError(t, err)
+	require.True(t, reserved)
+	time.Sleep(10 * time.Millisecond)
+
+	rec, reserved, err := s.Reserve(ctx, "lease-key", "fingerprint")
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	require.NoError(t, s.Release(ctx, stale))
+	stale.StatusCode = http.StatusCreated
+	assert.ErrorIs(t, s.Complete(ctx, stale), idempotency.ErrNotFound)
+	rec.StatusCode = http.StatusCreated
+	assert.NoError(t, s.Complete(ctx, rec))
+}
+
+func TestStore_Purge(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := idempotency.NewStore(db, time.Hour, time.Minute)
+	ctx := context.Background()
+
+	n, err := s.Purge(ctx, time.Now().Add(time.Minute))
+
+	require.NoError(t, err)
+	assert.Positive(t, n)
+	_, reserved, err := s.Reserve(ctx, "completed-key", "fingerprint")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
@@ -0,0 +1,19 @@
+INSERT INTO idempotency_keys (key, fingerprint, status_code, header, body, created_at)
+VALUES (
+  'completed-key',
+  'fingerprint',
+  201,
+  '{"Location": ["/news/3b082d9d-1dc7-4d1f-907e-50d449a03d45"]}',
+  '{}',
+  NOW());
+INSERT INTO idempotency_keys (key, fingerprint, created_at)
+VALUES (
+  'expired-key',
+  'fingerprint',
+  NOW() - INTERVAL '2 days');
+INSERT INTO idempotency_keys (key, fingerprint, created_at, locked_until)
+VALUES (
+  'abandoned-key',
+  'fingerprint',
+  NOW() - INTERVAL '10 minutes',
+  NOW() - INTERVAL '9 minutes');
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 )
 
 // CtxKey for the logger.
@@ -40,11 +41,54 @@ func AddLoggerMid(logger *slog.Logger, next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// Middleware request logging middleware.
+// Middleware writes an access log line once the request is served. The
+// route is the pattern of the ServeMux handler, so next must be the mux or
+// pass the request to it unchanged.
 func Middleware(next http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		l := FromContext(r.Context())
-		l.Info("request", "path", r.URL.String())
-		next.ServeHTTP(w, r)
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		FromContext(r.Context()).Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", r.Pattern,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"latency", time.Since(start),
+			"remoteAddr", r.RemoteAddr,
+			"userAgent", r.UserAgent(),
+		)
 	}
 }
+
+// responseRecorder records the status code and the size of the response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (rr *responseRecorder) WriteHeader(status int) {
+	if !rr.wroteHeader {
+		rr.status = status
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	n, err := rr.ResponseWriter.Write(b)
+	rr.bytes += n
+	return n, err
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
@@ -1,13 +1,18 @@
 package logger_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/prashsamosa/newsapi/internal/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_CtxWithLogger(t *testing.T) {
@@ -85,3 +90,57 @@ func Test_FromContext(t *testing.T) {
 		})
 	}
 }
+
+func Test_Middleware(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		expectedRoute  string
+		expectedStatus float64
+		expectedBytes  float64
+	}{
+		{
+			name:           "route",
+			path:           "/news/1",
+			expectedRoute:  "GET /news/{news_id}",
+			expectedStatus: http.StatusCreated,
+			expectedBytes:  5,
+		},
+		{
+			name:           "no route",
+			path:           "/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBytes:  19,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /news/{news_id}", func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte("hello"))
+			})
+			var logs bytes.Buffer
+			r := httptest.NewRequest(http.MethodGet, tc.path, http.NoBody)
+			r.Header.Set("User-Agent", "test-agent")
+			r = r.WithContext(logger.CtxWithLogger(r.Context(), slog.New(slog.NewJSONHandler(&logs, nil))))
+
+			// Act
+			logger.Middleware(mux)(httptest.NewRecorder(), r)
+
+			// Assert
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			assert.Equal(t, http.MethodGet, entry["method"])
+			assert.Equal(t, tc.path, entry["path"])
+			assert.Equal(t, tc.expectedRoute, entry["route"])
+			assert.Equal(t, tc.expectedStatus, entry["status"])
+			assert.Equal(t, tc.expectedBytes, entry["bytes"])
+			assert.Equal(t, "test-agent", entry["userAgent"])
+			assert.Equal(t, "192.0.2.1:1234", entry["remoteAddr"])
+			assert.Contains(t, entry, "latency")
+		})
+	}
+}
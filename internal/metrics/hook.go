@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uptrace/bun"
+)
+
+// QueryHook is a bun query hook recording the duration of the queries.
+type QueryHook struct {
+	duration *prometheus.HistogramVec
+}
+
+var _ bun.QueryHook = (*QueryHook)(nil)
+
+// BeforeQuery implements bun.QueryHook.
+func (h *QueryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery implements bun.QueryHook. Queries returning no rows are not
+// counted as errors.
+func (h *QueryHook) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	result := "ok"
+	if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+		result = "error"
+	}
+	h.duration.WithLabelValues(event.Operation(), result).Observe(time.Since(event.StartTime).Seconds())
+}
@@ -0,0 +1,89 @@
+package metrics_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// scrape returns the metrics served by the handler.
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics_Instrument(t *testing.T) {
+	// Arrange
+	m := metrics.New()
+	h := m.Instrument("GET /news/{news_id}", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	// Act
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/news/1", http.NoBody))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/news/2", http.NoBody))
+
+	// Assert
+	out := scrape(t, m)
+	assert.Contains(t, out, `newsapi_http_requests_total{code="404",method="get",route="GET /news/{news_id}"} 2`)
+	assert.Contains(t, out, `newsapi_http_request_duration_seconds_count{code="404",method="get",route="GET /news/{news_id}"} 2`)
+	assert.Contains(t, out, "newsapi_http_requests_in_flight 0")
+}
+
+func TestQueryHook(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedResult string
+	}{
+		{name: "success", expectedResult: "ok"},
+		{name: "no rows", err: sql.ErrNoRows, expectedResult: "ok"},
+		{name: "failure", err: errors.New("db error"), expectedResult: "error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			m := metrics.New()
+			hook := m.QueryHook()
+
+			// Act
+			hook.AfterQuery(context.Background(), &bun.QueryEvent{
+				Query:     "SELECT 1",
+				StartTime: time.Now(),
+				Err:       tc.err,
+			})
+
+			// Assert
+			assert.Contains(t, scrape(t, m), `newsapi_db_query_duration_seconds_count{operation="SELECT",result="`+tc.expectedResult+`"} 1`)
+		})
+	}
+}
+
+func TestMetrics_InstrumentDB(t *testing.T) {
+	// Arrange
+	m := metrics.New()
+	db := bun.NewDB(&sql.DB{}, pgdialect.New())
+
+	// Act
+	m.InstrumentDB(db)
+
+	// Assert
+	assert.Contains(t, scrape(t, m), `go_sql_open_connections{db_name="newsapi"} 0`)
+}
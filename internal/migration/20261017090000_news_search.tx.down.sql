@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS news_search_vector_idx;
+
+ALTER TABLE news DROP COLUMN IF EXISTS search_vector;
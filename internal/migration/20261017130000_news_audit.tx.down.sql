@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS news_audit;
+DROP FUNCTION IF EXISTS news_audit_append_only();
+ALTER TABLE news DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE news DROP COLUMN IF EXISTS created_by;
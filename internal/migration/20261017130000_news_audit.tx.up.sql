@@ -0,0 +1,25 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS created_by TEXT;
+ALTER TABLE news ADD COLUMN IF NOT EXISTS updated_by TEXT;
+
+CREATE TABLE IF NOT EXISTS news_audit (
+  id BIGSERIAL PRIMARY KEY,
+  news_id UUID NOT NULL,
+  action TEXT NOT NULL,
+  actor TEXT,
+  request_id TEXT,
+  changes JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS news_audit_news_id_idx ON news_audit (news_id, id);
+CREATE INDEX IF NOT EXISTS news_audit_created_at_idx ON news_audit (created_at);
+
+CREATE OR REPLACE FUNCTION news_audit_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'news_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER news_audit_append_only
+  BEFORE UPDATE OR DELETE ON news_audit
+  FOR EACH ROW EXECUTE FUNCTION news_audit_append_only();
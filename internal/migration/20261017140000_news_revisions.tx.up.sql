@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS news_revisions (
+  news_id UUID NOT NULL REFERENCES news (id) ON DELETE CASCADE,
+  revision INTEGER NOT NULL,
+  author TEXT NOT NULL,
+  title TEXT NOT NULL,
+  summary TEXT NOT NULL,
+  content TEXT NOT NULL,
+  source TEXT NOT NULL,
+  tags TEXT[] NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  revised_by TEXT,
+  revised_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (news_id, revision)
+);
+
+INSERT INTO news_revisions (news_id, revision, author, title, summary, content, source, tags, created_at, revised_by, revised_at)
+SELECT id, version, author, title, summary, content, source, tags, created_at, updated_by, updated_at
+FROM news
+ON CONFLICT DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE news DROP COLUMN IF EXISTS status;
@@ -0,0 +1,6 @@
+-- Existing news stay live, new ones start as drafts.
+ALTER TABLE news ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published'
+  CHECK (status IN ('draft', 'review', 'published', 'archived'));
+ALTER TABLE news ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE INDEX IF NOT EXISTS news_status_idx ON news (status);
@@ -0,0 +1,2 @@
+ALTER TABLE news DROP COLUMN IF EXISTS expire_at;
+ALTER TABLE news DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,5 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE news ADD COLUMN IF NOT EXISTS expire_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS news_publish_at_idx ON news (publish_at) WHERE status = 'review';
+CREATE INDEX IF NOT EXISTS news_expire_at_idx ON news (expire_at) WHERE status = 'published';
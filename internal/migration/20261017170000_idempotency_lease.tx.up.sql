@@ -0,0 +1,5 @@
+-- The keys in progress are held for a short lease, so that the key of a
+-- request lost on a crash can be retried before it expires.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE;
+
+UPDATE idempotency_keys SET locked_until = created_at WHERE status_code IS NULL;
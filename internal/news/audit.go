@@ -0,0 +1,162 @@
+package news
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/auth"
+	"github.com/prashsamosa/newsapi/internal/requestid"
+	"github.com/uptrace/bun"
+)
+
+// AuditAction is the kind of change recorded in the audit log.
+type AuditAction string
+
+// Audited actions.
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	AuditPurge   AuditAction = "purge"
+	// AuditTransition is a change of the editorial status.
+	AuditTransition AuditAction = "transition"
+)
+
+// Change holds the old and new value of a changed field.
+type Change struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+// AuditEntry used to represent a change of a news in the append-only audit
+// log.
+type AuditEntry struct {
+	bun.BaseModel `bun:"table:news_audit"`
+	ID            int64             `bun:"id,pk,autoincrement" json:"id"`
+	NewsID        uuid.UUID         `bun:"news_id,type:uuid,notnull" json:"news_id"`
+	Action        AuditAction       `bun:"action,notnull" json:"action"`
+	Actor         string            `bun:"actor,nullzero" json:"actor,omitempty"`
+	RequestID     string            `bun:"request_id,nullzero" json:"request_id,omitempty"`
+	Changes       map[string]Change `bun:"changes,type:jsonb,notnull" json:"changes"`
+	CreatedAt     time.Time         `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// AuditFilter narrows down the audit log entries.
+type AuditFilter struct {
+	NewsID uuid.UUID
+	Actor  string
+	Action AuditAction
+	// Since and Until bound the time of the entries, inclusive and
+	// exclusive.
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+	Offset int
+}
+
+// FindAudit returns the audit log entries matching the filter, oldest first,
+// and the number of matching entries.
+func (s Store) FindAudit(ctx context.Context, f AuditFilter) ([]*AuditEntry, int, error) {
+	entries := []*AuditEntry{}
+	q := s.db.NewSelect().Model(&entries)
+	if f.NewsID != uuid.Nil {
+		q = q.Where("news_id = ?", f.NewsID)
+	}
+	if f.Actor != "" {
+		q = q.Where("actor = ?", f.Actor)
+	}
+	if f.Action != "" {
+		q = q.Where("action = ?", f.Action)
+	}
+	if !f.Since.IsZero() {
+		q = q.Where("created_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		q = q.Where("created_at < ?", f.Until)
+	}
+	total, err := q.OrderExpr("id ASC").Limit(f.Limit).Offset(f.Offset).ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, NewCustomError(err, http.StatusInternalServerError)
+	}
+	return entries, total, nil
+}
+
+// audit appends the entries to the audit log.
+func (s Store) audit(ctx context.Context, entries ...*AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if _, err := s.db.NewInsert().Model(&entries).Exec(ctx); err != nil {
+		return NewCustomError(err, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// newAuditEntry returns an entry of the change made by the principal and
+// the request of the context.
+func newAuditEntry(ctx context.Context, action AuditAction, newsID uuid.UUID, changes map[string]Change) *AuditEntry {
+	return &AuditEntry{
+		NewsID:    newsID,
+		Action:    action,
+		Actor:     actor(ctx),
+		RequestID: requestid.FromContext(ctx),
+		Changes:   changes,
+	}
+}
+
+// actor returns the subject of the principal making the change, empty for
+// changes made by the system.
+func actor(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return p.Subject
+	}
+	return ""
+}
+
+// auditedFields are the news fields compared in the audit log.
+var auditedFields = []struct {
+	name  string
+	value func(*Record) any
+}{
+	{"author", func(r *Record) any { return r.Author }},
+	{"title", func(r *Record) any { return r.Title }},
+	{"summary", func(r *Record) any { return r.Summary }},
+	{"content", func(r *Record) any { return r.Content }},
+	{"source", func(r *Record) any { return r.Source }},
+	{"tags", func(r *Record) any { return r.Tags }},
+	{"created_at", func(r *Record) any { return r.CreatedAt.UTC() }},
+	{"publish_at", func(r *Record) any { return auditTime(r.PublishAt) }},
+	{"expire_at", func(r *Record) any { return auditTime(r.ExpireAt) }},
+}
+
+// auditTime returns the time in UTC, or nil when it is not set.
+func auditTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC()
+}
+
+// diff returns the changed fields between the old and new news, either of
+// which can be nil for a news created or removed.
+func diff(old, news *Record) map[string]Change {
+	changes := make(map[string]Change)
+	for _, f := range auditedFields {
+		var c Change
+		if old != nil {
+			c.Old = f.value(old)
+		}
+		if news != nil {
+			c.New = f.value(news)
+		}
+		if old != nil && news != nil && reflect.DeepEqual(c.Old, c.New) {
+			continue
+		}
+		changes[f.name] = c
+	}
+	return changes
+}
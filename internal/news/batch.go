@@ -0,0 +1,155 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// BatchOp is the kind of write of a batch operation.
+type BatchOp string
+
+// Batch operations.
+const (
+	BatchCreate BatchOp = "create"
+	BatchUpdate BatchOp = "update"
+	BatchDelete BatchOp = "delete"
+)
+
+// BatchOperation is a single write of a batch.
+type BatchOperation struct {
+	Op BatchOp
+	// ID of the news to update or delete.
+	ID uuid.UUID
+	// Version, when set, makes the update or delete conditional on the
+	// stored version.
+	Version int
+	// Record is the news to create or update.
+	Record *Record
+}
+
+// BatchResult is the outcome of a batch operation.
+type BatchResult struct {
+	// Record is the created or updated news.
+	Record *Record
+	Err    error
+}
+
+// errBatchFailed rolls back the transaction of an atomic batch.
+var errBatchFailed = errors.New("batch operation failed")
+
+// CreateMany creates the news records with a single multi-row insert and
+// records them in the audit log.
+func (s Store) CreateMany(ctx context.Context, news []*Record) ([]*Record, error) {
+	if len(news) == 0 {
+		return news, nil
+	}
+	entries := make([]*AuditEntry, 0, len(news))
+	for _, n := range news {
+		n.ID = uuid.New()
+		n.CreatedBy = actor(ctx)
+		n.UpdatedBy = n.CreatedBy
+		entries = append(entries, newAuditEntry(ctx, AuditCreate, n.ID, diff(nil, n)))
+	}
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(&news).Exec(ctx); err != nil {
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		if err := NewStore(tx).snapshot(ctx, news...); err != nil {
+			return err
+		}
+		return NewStore(tx).audit(ctx, entries...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return news, nil
+}
+
+// Batch runs the operations and returns their results in the same order.
+// The creates are inserted together before the other operations run.
+//
+// An atomic batch runs in a single transaction and stops at the first
+// failed operation: nothing is applied, the failed operation reports its
+// error and the others ErrBatchAborted. Otherwise every operation is
+// applied on its own.
+func (s Store) Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	results := make([]BatchResult, len(ops))
+	if !atomic {
+		s.runBatch(ctx, ops, results, false)
+		return results, nil
+	}
+
+	failed := -1
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if failed = NewStore(tx).runBatch(ctx, ops, results, true); failed >= 0 {
+			return errBatchFailed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchFailed) {
+		return nil, NewCustomError(err, http.StatusInternalServerError)
+	}
+	if failed >= 0 {
+		for i := range results {
+			if i != failed {
+				results[i] = BatchResult{Err: NewCustomError(ErrBatchAborted, http.StatusFailedDependency).WithCode(CodeBatchAborted)}
+			}
+		}
+	}
+	return results, nil
+}
+
+// runBatch runs the operations, writing their outcome to results. When
+// stopOnError is set, it stops at the first failed operation and returns
+// its index, -1 when all succeeded.
+func (s Store) runBatch(ctx context.Context, ops []BatchOperation, results []BatchResult, stopOnError bool) int {
+	var (
+		creates []*Record
+		idx     []int
+	)
+	for i, op := range ops {
+		if op.Op == BatchCreate {
+			creates = append(creates, op.Record)
+			idx = append(idx, i)
+		}
+	}
+	// The bulk insert runs in its own transaction, a savepoint within an
+	// atomic batch, so that a failure can be narrowed down to the record.
+	_, err := s.CreateMany(ctx, creates)
+	for j, n := range creates {
+		if err == nil {
+			results[idx[j]].Record = n
+			continue
+		}
+		results[idx[j]].Record, results[idx[j]].Err = s.Create(ctx, n)
+		if results[idx[j]].Err != nil && stopOnError {
+			return idx[j]
+		}
+	}
+
+	for i, op := range ops {
+		switch op.Op {
+		case BatchCreate:
+			continue
+		case BatchUpdate:
+			op.Record.Version = op.Version
+			if err := s.UpdateByID(ctx, op.ID, op.Record); err != nil {
+				results[i].Err = err
+			} else {
+				results[i].Record = op.Record
+			}
+		case BatchDelete:
+			results[i].Err = s.DeleteByID(ctx, op.ID, DeleteOptions{Version: op.Version})
+		default:
+			results[i].Err = NewCustomError(errors.New("unknown batch operation: "+string(op.Op)), http.StatusBadRequest)
+		}
+		if results[i].Err != nil && stopOnError {
+			return i
+		}
+	}
+	return -1
+}
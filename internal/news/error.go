@@ -1,10 +1,35 @@
 package news
 
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Machine readable codes of the store errors.
+const (
+	CodeNotFound          = "news_not_found"
+	CodeRevisionNotFound  = "revision_not_found"
+	CodeVersionMismatch   = "news_version_mismatch"
+	CodeInvalidCursor     = "invalid_cursor"
+	CodeBatchAborted      = "batch_aborted"
+	CodeInvalidTransition = "invalid_status_transition"
+)
+
+// ErrVersionMismatch is returned when a conditional write does not match
+// the current version of the news.
+var ErrVersionMismatch = errors.New("news version mismatch")
+
+// ErrBatchAborted is reported for the operations of an atomic batch that
+// were rolled back because another operation failed.
+var ErrBatchAborted = errors.New("batch aborted by a failed operation")
+
 // CustomError represents the error state of
 // database error.
 type CustomError struct {
 	err        error
 	httpStatus int
+	code       string
 }
 
 // NewCustomError returns an instance of customer error.
@@ -15,6 +40,12 @@ func NewCustomError(err error, httpStatus int) *CustomError {
 	}
 }
 
+// WithCode sets the machine readable code of the error.
+func (ce *CustomError) WithCode(code string) *CustomError {
+	ce.code = code
+	return ce
+}
+
 // Error implements the error interface.
 func (ce CustomError) Error() string {
 	return ce.err.Error()
@@ -29,3 +60,12 @@ func (ce CustomError) Unwrap() error {
 func (ce CustomError) HTTPStatusCode() int {
 	return ce.httpStatus
 }
+
+// Code is the machine readable code of the error. It defaults to the snake
+// cased HTTP status text, e.g. "internal_server_error".
+func (ce CustomError) Code() string {
+	if ce.code != "" {
+		return ce.code
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(ce.httpStatus)), " ", "_")
+}
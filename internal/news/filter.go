@@ -0,0 +1,79 @@
+package news
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// TagMatch controls how multiple tags in a filter are matched.
+type TagMatch string
+
+const (
+	// TagMatchAny matches records having at least one of the tags.
+	TagMatchAny TagMatch = "any"
+	// TagMatchAll matches records having every one of the tags.
+	TagMatchAll TagMatch = "all"
+)
+
+// Filter narrows down a news listing. Zero value fields are ignored.
+type Filter struct {
+	Author        string
+	Tags          []string
+	TagMatch      TagMatch
+	SourceHost    string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedSince  time.Time
+	// Statuses restricts the listing to the news in one of the states.
+	Statuses []Status
+	// LiveAt restricts the listing to the news that are live at that time,
+	// see Record.Live.
+	LiveAt time.Time
+}
+
+// liveAt adds the conditions of the news live at the time to the query.
+func liveAt(q *bun.SelectQuery, at time.Time) *bun.SelectQuery {
+	return q.
+		Where("?TableAlias.status = ?", StatusPublished).
+		Where("(?TableAlias.publish_at IS NULL OR ?TableAlias.publish_at <= ?)", at).
+		Where("(?TableAlias.expire_at IS NULL OR ?TableAlias.expire_at > ?)", at)
+}
+
+// sourceHostExpr extracts the lower cased host from the source URL. The
+// question marks are escaped so bun does not treat them as placeholders.
+const sourceHostExpr = `lower(substring(source from '^[a-zA-Z][a-zA-Z0-9+.-]*://(\?:[^@/]*@)\?([^/:\?#]+)'))`
+
+// apply adds the filter conditions to the query.
+func (f Filter) apply(q *bun.SelectQuery) *bun.SelectQuery {
+	if f.Author != "" {
+		q = q.Where("author = ?", f.Author)
+	}
+	if len(f.Tags) > 0 {
+		if f.TagMatch == TagMatchAll {
+			q = q.Where("tags @> ?", pgdialect.Array(f.Tags))
+		} else {
+			q = q.Where("tags && ?", pgdialect.Array(f.Tags))
+		}
+	}
+	if f.SourceHost != "" {
+		q = q.Where(sourceHostExpr+" = lower(?)", f.SourceHost)
+	}
+	if !f.CreatedAfter.IsZero() {
+		q = q.Where("created_at > ?", f.CreatedAfter)
+	}
+	if !f.CreatedBefore.IsZero() {
+		q = q.Where("created_at < ?", f.CreatedBefore)
+	}
+	if !f.UpdatedSince.IsZero() {
+		q = q.Where("updated_at >= ?", f.UpdatedSince)
+	}
+	if len(f.Statuses) > 0 {
+		q = q.Where("status IN (?)", bun.In(f.Statuses))
+	}
+	if !f.LiveAt.IsZero() {
+		q = liveAt(q, f.LiveAt)
+	}
+	return q
+}
@@ -0,0 +1,75 @@
+package news
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListParams holds the options for a paged news listing.
+type ListParams struct {
+	// Limit is the maximum number of records in the page.
+	Limit int
+	// Offset skips the given number of records. It is ignored when a
+	// cursor is set.
+	Offset int
+	// Cursor continues the listing from a previously returned page.
+	Cursor *Cursor
+	// Filter narrows down the records in the listing.
+	Filter Filter
+	// Sort is the order of the listing, DefaultSort when empty. Cursors
+	// are only returned and accepted for the default order.
+	Sort []SortField
+}
+
+// Page represents a single page of news records.
+type Page struct {
+	Records    []*Record
+	NextCursor *Cursor
+	PrevCursor *Cursor
+	// TotalHint is the number of records matching the listing at the time
+	// the page was read.
+	TotalHint int
+}
+
+// Cursor is a keyset position in the news listing built from the
+// (created_at, id) pair of a record.
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+	// Backward is set when the cursor points to the previous page.
+	Backward bool `json:"b,omitempty"`
+}
+
+// Encode returns the opaque string representation of the cursor.
+func (c Cursor) Encode() string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		// Marshalling a time and a uuid cannot fail.
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode cursor: %w", err)
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("unmarshal cursor: %w", err)
+	}
+	if c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return nil, fmt.Errorf("invalid cursor: %s", s)
+	}
+	return &c, nil
+}
+
+func cursorOf(r *Record, backward bool) *Cursor {
+	return &Cursor{CreatedAt: r.CreatedAt, ID: r.ID, Backward: backward}
+}
@@ -20,4 +20,20 @@ type Record struct {
 	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 	DeletedAt     time.Time `bun:"deleted_at,nullzero,soft_delete"`
+	Version       int       `bun:"version,nullzero,notnull,default:1"`
+	CreatedBy     string    `bun:"created_by,nullzero"`
+	UpdatedBy     string    `bun:"updated_by,nullzero"`
+	Status        Status    `bun:"status,nullzero,notnull,default:'draft'"`
+	// PublishAt is when a news in review is published, and ExpireAt when a
+	// published news is archived.
+	PublishAt time.Time `bun:"publish_at,nullzero"`
+	ExpireAt  time.Time `bun:"expire_at,nullzero"`
+}
+
+// Live reports whether the news is published and within its publication
+// window at the given time.
+func (r *Record) Live(at time.Time) bool {
+	return r.Status == StatusPublished &&
+		(r.PublishAt.IsZero() || !at.Before(r.PublishAt)) &&
+		(r.ExpireAt.IsZero() || at.Before(r.ExpireAt))
 }
@@ -0,0 +1,154 @@
+package news
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Revision used to represent a snapshot of a news after a change. The
+// revision number is the version of the news it was taken from.
+type Revision struct {
+	bun.BaseModel `bun:"table:news_revisions"`
+	NewsID        uuid.UUID `bun:"news_id,pk,type:uuid" json:"news_id"`
+	Revision      int       `bun:"revision,pk" json:"revision"`
+	Author        string    `bun:"author,notnull" json:"author"`
+	Title         string    `bun:"title,notnull" json:"title"`
+	Summary       string    `bun:"summary,notnull" json:"summary"`
+	Content       string    `bun:"content,notnull" json:"content"`
+	Source        string    `bun:"source,notnull" json:"source"`
+	Tags          []string  `bun:"tags,notnull,array" json:"tags"`
+	CreatedAt     time.Time `bun:"created_at,notnull" json:"created_at"`
+	RevisedBy     string    `bun:"revised_by,nullzero" json:"revised_by,omitempty"`
+	RevisedAt     time.Time `bun:"revised_at,nullzero,notnull,default:current_timestamp" json:"revised_at"`
+}
+
+// newRevision returns the snapshot of the news at its current version.
+func newRevision(news *Record) *Revision {
+	return &Revision{
+		NewsID:    news.ID,
+		Revision:  news.Version,
+		Author:    news.Author,
+		Title:     news.Title,
+		Summary:   news.Summary,
+		Content:   news.Content,
+		Source:    news.Source,
+		Tags:      news.Tags,
+		CreatedAt: news.CreatedAt,
+		RevisedBy: news.UpdatedBy,
+		RevisedAt: news.UpdatedAt,
+	}
+}
+
+// Record returns the news fields of the revision.
+func (r *Revision) Record() *Record {
+	return &Record{
+		ID:        r.NewsID,
+		Author:    r.Author,
+		Title:     r.Title,
+		Summary:   r.Summary,
+		Content:   r.Content,
+		Source:    r.Source,
+		Tags:      r.Tags,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// FindRevisions returns a page of the revisions of a news, latest first,
+// and the number of revisions.
+func (s Store) FindRevisions(ctx context.Context, id uuid.UUID, limit, offset int) ([]*Revision, int, error) {
+	revisions := []*Revision{}
+	total, err := s.db.NewSelect().
+		Model(&revisions).
+		Where("news_id = ?", id).
+		OrderExpr("revision DESC").
+		Limit(limit).
+		Offset(offset).
+		ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, NewCustomError(err, http.StatusInternalServerError)
+	}
+	if total == 0 {
+		if _, err := s.FindByID(ctx, id); err != nil {
+			return nil, 0, err
+		}
+	}
+	return revisions, total, nil
+}
+
+// FindRevision returns a single revision of a news.
+func (s Store) FindRevision(ctx context.Context, id uuid.UUID, revision int) (*Revision, error) {
+	var r Revision
+	err := s.db.NewSelect().
+		Model(&r).
+		Where("news_id = ?", id).
+		Where("revision = ?", revision).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewCustomError(err, http.StatusNotFound).WithCode(CodeRevisionNotFound)
+		}
+		return nil, NewCustomError(err, http.StatusInternalServerError)
+	}
+	return &r, nil
+}
+
+// DiffRevisions returns the fields changed between two revisions of a news.
+func (s Store) DiffRevisions(ctx context.Context, id uuid.UUID, from, to int) (map[string]Change, error) {
+	old, err := s.FindRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	news, err := s.FindRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	return diff(old.Record(), news.Record()), nil
+}
+
+// RestoreRevision updates the news with the content of one of its
+// revisions, which creates a new revision. The publishing window is not
+// part of the content and is kept. When the version is set, the restore
+// only succeeds if it matches the stored version.
+func (s Store) RestoreRevision(ctx context.Context, id uuid.UUID, revision, version int) (*Record, error) {
+	var news *Record
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		current, err := NewStore(tx).findForUpdate(ctx, id, version)
+		if err != nil {
+			return err
+		}
+		r, err := NewStore(tx).FindRevision(ctx, id, revision)
+		if err != nil {
+			return err
+		}
+		news = r.Record()
+		news.PublishAt = current.PublishAt
+		news.ExpireAt = current.ExpireAt
+		news.Version = version
+		return NewStore(tx).UpdateByID(ctx, id, news)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return news, nil
+}
+
+// snapshot stores the revisions of the news at their current version.
+func (s Store) snapshot(ctx context.Context, news ...*Record) error {
+	revisions := make([]*Revision, 0, len(news))
+	for _, n := range news {
+		revisions = append(revisions, newRevision(n))
+	}
+	if len(revisions) == 0 {
+		return nil
+	}
+	if _, err := s.db.NewInsert().Model(&revisions).Exec(ctx); err != nil {
+		return NewCustomError(err, http.StatusInternalServerError)
+	}
+	return nil
+}
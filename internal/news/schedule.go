@@ -0,0 +1,56 @@
+package news
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// PublishDue publishes the news in review whose publish time has come and
+// returns how many were published.
+func (s Store) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	return s.transitionDue(ctx, StatusReview, StatusPublished, "publish_at", now)
+}
+
+// ExpireDue archives the published news whose expiry time has come and
+// returns how many were archived.
+func (s Store) ExpireDue(ctx context.Context, now time.Time) (int64, error) {
+	return s.transitionDue(ctx, StatusPublished, StatusArchived, "expire_at", now)
+}
+
+// transitionDue moves the news in the from state whose time column is
+// before now to the to state. The transitions are made by the system,
+// recorded like the ones made through the API.
+func (s Store) transitionDue(ctx context.Context, from, to Status, column string, now time.Time) (int64, error) {
+	var moved []*Record
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model(&moved).
+			Set("status = ?", to).
+			Set("updated_at = ?", now).
+			Set("updated_by = NULL").
+			Set("version = version + 1").
+			Where("status = ?", from).
+			Where("? <= ?", bun.Ident(column), now).
+			Returning("?TableColumns").
+			Exec(ctx)
+		if err != nil {
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		if err := NewStore(tx).snapshot(ctx, moved...); err != nil {
+			return err
+		}
+		entries := make([]*AuditEntry, 0, len(moved))
+		for _, n := range moved {
+			changes := map[string]Change{"status": {Old: from, New: to}}
+			entries = append(entries, newAuditEntry(ctx, AuditTransition, n.ID, changes))
+		}
+		return NewStore(tx).audit(ctx, entries...)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(moved)), nil
+}
@@ -0,0 +1,66 @@
+package news
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// headlineOptions are the ts_headline options used for the snippets.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=3"
+
+// SearchParams holds the options for a full-text search.
+type SearchParams struct {
+	// Query is a web search style query, see websearch_to_tsquery.
+	Query  string
+	Limit  int
+	Offset int
+	// Statuses restricts the search to the news in one of the states.
+	Statuses []Status
+	// LiveAt restricts the search to the news that are live at that time.
+	LiveAt time.Time
+}
+
+// SearchResult is a news record matching a full-text search along with its
+// rank and highlighted snippets.
+type SearchResult struct {
+	Record
+	Rank             float64 `bun:"rank" json:"rank"`
+	TitleHighlight   string  `bun:"title_highlight" json:"title_highlight"`
+	SummaryHighlight string  `bun:"summary_highlight" json:"summary_highlight"`
+	ContentHighlight string  `bun:"content_highlight" json:"content_highlight"`
+}
+
+// Search runs a full-text search over the title, summary and content of the
+// news, best matches first.
+func (s Store) Search(ctx context.Context, params SearchParams) ([]*SearchResult, error) {
+	results := []*SearchResult{}
+	err := s.db.NewSelect().
+		Model((*Record)(nil)).
+		TableExpr("websearch_to_tsquery('english', ?) AS query", params.Query).
+		ColumnExpr("?TableColumns").
+		ColumnExpr("ts_rank_cd(record.search_vector, query) AS rank").
+		ColumnExpr("ts_headline('english', record.title, query, ?) AS title_highlight", headlineOptions).
+		ColumnExpr("ts_headline('english', record.summary, query, ?) AS summary_highlight", headlineOptions).
+		ColumnExpr("ts_headline('english', record.content, query, ?) AS content_highlight", headlineOptions).
+		Where("record.search_vector @@ query").
+		Apply(func(q *bun.SelectQuery) *bun.SelectQuery {
+			if len(params.Statuses) > 0 {
+				q = q.Where("record.status IN (?)", bun.In(params.Statuses))
+			}
+			if !params.LiveAt.IsZero() {
+				q = liveAt(q, params.LiveAt)
+			}
+			return q
+		}).
+		OrderExpr("rank DESC, record.created_at DESC, record.id DESC").
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Scan(ctx, &results)
+	if err != nil {
+		return nil, NewCustomError(err, http.StatusInternalServerError)
+	}
+	return results, nil
+}
@@ -0,0 +1,72 @@
+package news
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// SortField is a single column of a listing sort order.
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// DefaultSort is the listing order used when none is requested: newest
+// first, with the id as tiebreaker so the order is stable across pages.
+var DefaultSort = []SortField{
+	{Column: "created_at", Desc: true},
+	{Column: "id", Desc: true},
+}
+
+// SortableColumns are the record columns a listing can be sorted by.
+var SortableColumns = []string{"created_at", "updated_at", "title", "author", "id"}
+
+// ParseSort parses a comma separated sort order such as "-created_at,title",
+// where a leading "-" sorts the column in descending order. The id is added
+// as the last column when missing to keep the order stable.
+func ParseSort(s string) ([]SortField, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, errors.New("sort cannot be empty")
+	}
+	var fields []SortField
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		f := SortField{Column: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !slices.Contains(SortableColumns, f.Column) {
+			return nil, fmt.Errorf("cannot sort by %q, must be one of %s", f.Column, strings.Join(SortableColumns, ", "))
+		}
+		if slices.ContainsFunc(fields, func(sf SortField) bool { return sf.Column == f.Column }) {
+			return nil, fmt.Errorf("cannot sort by %q more than once", f.Column)
+		}
+		fields = append(fields, f)
+	}
+	if !slices.ContainsFunc(fields, func(sf SortField) bool { return sf.Column == "id" }) {
+		fields = append(fields, SortField{Column: "id", Desc: fields[0].Desc})
+	}
+	return fields, nil
+}
+
+// IsDefaultSort reports whether the sort order is the default one. Keyset
+// cursors are only supported on the default order.
+func IsDefaultSort(fields []SortField) bool {
+	return len(fields) == 0 || slices.Equal(fields, DefaultSort)
+}
+
+// applySort adds the sort order to the query.
+func applySort(q *bun.SelectQuery, fields []SortField) *bun.SelectQuery {
+	if len(fields) == 0 {
+		fields = DefaultSort
+	}
+	for _, f := range fields {
+		if f.Desc {
+			q = q.OrderExpr("? DESC", bun.Ident(f.Column))
+		} else {
+			q = q.OrderExpr("? ASC", bun.Ident(f.Column))
+		}
+	}
+	return q
+}
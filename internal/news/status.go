@@ -0,0 +1,96 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Status is the editorial state of a news. Only published news are public.
+type Status string
+
+// Editorial states.
+const (
+	StatusDraft     Status = "draft"
+	StatusReview    Status = "review"
+	StatusPublished Status = "published"
+	StatusArchived  Status = "archived"
+)
+
+// Statuses lists the editorial states in workflow order.
+var Statuses = []Status{StatusDraft, StatusReview, StatusPublished, StatusArchived}
+
+// transitions are the states reachable from each state. A news moves
+// forward through the workflow one step at a time, and can go back to draft
+// from any other state.
+var transitions = map[Status][]Status{
+	StatusDraft:     {StatusReview},
+	StatusReview:    {StatusPublished, StatusDraft},
+	StatusPublished: {StatusArchived, StatusDraft},
+	StatusArchived:  {StatusDraft},
+}
+
+// ErrInvalidTransition is returned when a news cannot move to the
+// requested state from its current one.
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ParseStatus returns the status of its name.
+func ParseStatus(s string) (Status, error) {
+	if !slices.Contains(Statuses, Status(s)) {
+		return "", fmt.Errorf("unknown status: %s", s)
+	}
+	return Status(s), nil
+}
+
+// CanTransition reports whether a news can move from a state to another.
+func CanTransition(from, to Status) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// Transition moves a news to another editorial state. When the version is
+// set, the transition only succeeds if it matches the stored version. The
+// transition bumps the version like any other update.
+func (s Store) Transition(ctx context.Context, id uuid.UUID, to Status, version int) (*Record, error) {
+	var news *Record
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		news, err = NewStore(tx).findForUpdate(ctx, id, version)
+		if err != nil {
+			return err
+		}
+		from := news.Status
+		if !CanTransition(from, to) {
+			return NewCustomError(fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, from, to), http.StatusConflict).
+				WithCode(CodeInvalidTransition)
+		}
+
+		news.Status = to
+		news.UpdatedAt = time.Now()
+		news.UpdatedBy = actor(ctx)
+		_, err = tx.NewUpdate().
+			Model(news).
+			Column("status", "updated_at", "updated_by", "version").
+			Value("version", "version + 1").
+			WherePK().
+			Returning("version").
+			Exec(ctx)
+		if err != nil {
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		if err := NewStore(tx).snapshot(ctx, news); err != nil {
+			return err
+		}
+		changes := map[string]Change{"status": {Old: from, New: to}}
+		return NewStore(tx).audit(ctx, newAuditEntry(ctx, AuditTransition, id, changes))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return news, nil
+}
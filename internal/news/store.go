@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
@@ -25,8 +27,19 @@ func NewStore(db bun.IDB) *Store {
 // Create news record.
 func (s Store) Create(ctx context.Context, news *Record) (*Record, error) {
 	news.ID = uuid.New()
-	if err := s.db.NewInsert().Model(news).Returning("*").Scan(ctx, news); err != nil {
-		return nil, NewCustomError(err, http.StatusInternalServerError)
+	news.CreatedBy = actor(ctx)
+	news.UpdatedBy = news.CreatedBy
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(news).Exec(ctx); err != nil {
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		if err := NewStore(tx).snapshot(ctx, news); err != nil {
+			return err
+		}
+		return NewStore(tx).audit(ctx, newAuditEntry(ctx, AuditCreate, news.ID, diff(nil, news)))
+	})
+	if err != nil {
+		return nil, err
 	}
 	return news, nil
 }
@@ -36,47 +49,144 @@ func (s Store) FindByID(ctx context.Context, id uuid.UUID) (*Record, error) {
 	var news Record
 	if err := s.db.NewSelect().Model(&news).Where("id = ?", id).Scan(ctx); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, NewCustomError(err, http.StatusNotFound)
+			return nil, NewCustomError(err, http.StatusNotFound).WithCode(CodeNotFound)
 		}
 		return nil, NewCustomError(err, http.StatusInternalServerError)
 	}
 	return &news, nil
 }
 
-// FindAll returns all news store in the database.
+// FindAll returns all news store in the database in the default order.
 func (s Store) FindAll(ctx context.Context) ([]*Record, error) {
 	var news []*Record
-	if err := s.db.NewSelect().Model(&Record{}).Scan(ctx, &news); err != nil {
+	if err := applySort(s.db.NewSelect().Model(&Record{}), DefaultSort).Scan(ctx, &news); err != nil {
 		return nil, NewCustomError(err, http.StatusInternalServerError)
 	}
 	return news, nil
 }
 
-// DeleteByID deletes a news by its ID.
-func (s Store) DeleteByID(ctx context.Context, id uuid.UUID) (err error) {
-	_, err = s.db.NewDelete().Model(&Record{}).Where("id = ?", id).Returning("NULL").Exec(ctx)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil
-		}
-		return NewCustomError(err, http.StatusInternalServerError)
+// FindPage returns a single page of news in the requested order.
+func (s Store) FindPage(ctx context.Context, params ListParams) (*Page, error) {
+	keyset := IsDefaultSort(params.Sort)
+	if !keyset && params.Cursor != nil {
+		return nil, NewCustomError(errors.New("cursor requires the default sort order"), http.StatusBadRequest).WithCode(CodeInvalidCursor)
 	}
-	return nil
-}
 
-// UpdateByID update news by it's ID.
-func (s Store) UpdateByID(ctx context.Context, id uuid.UUID, news *Record) (err error) {
-	r, err := s.db.NewUpdate().Model(news).Where("id = ?", id).Returning("NULL").Exec(ctx)
+	total, err := params.Filter.apply(s.db.NewSelect().Model((*Record)(nil))).Count(ctx)
 	if err != nil {
-		return NewCustomError(err, http.StatusInternalServerError)
+		return nil, NewCustomError(err, http.StatusInternalServerError)
 	}
 
-	rowsAffected, err := r.RowsAffected()
-	if err != nil {
-		return NewCustomError(err, http.StatusInternalServerError)
+	var records []*Record
+	q := params.Filter.apply(s.db.NewSelect().Model(&records)).Limit(params.Limit + 1)
+	c := params.Cursor
+	switch {
+	case c == nil:
+		q = applySort(q, params.Sort).Offset(params.Offset)
+	case c.Backward:
+		q = q.Where("(created_at, id) > (?, ?)", c.CreatedAt, c.ID).OrderExpr("created_at ASC, id ASC")
+	default:
+		q = q.Where("(created_at, id) < (?, ?)", c.CreatedAt, c.ID).OrderExpr("created_at DESC, id DESC")
 	}
-	if rowsAffected == 0 {
-		return NewCustomError(err, http.StatusNotFound)
+	if err := q.Scan(ctx); err != nil {
+		return nil, NewCustomError(err, http.StatusInternalServerError)
+	}
+
+	hasMore := len(records) > params.Limit
+	if hasMore {
+		records = records[:params.Limit]
+	}
+	backward := c != nil && c.Backward
+	if backward {
+		slices.Reverse(records)
+	}
+
+	page := &Page{Records: records, TotalHint: total}
+	if len(records) == 0 || !keyset {
+		return page, nil
+	}
+	if hasMore || backward {
+		page.NextCursor = cursorOf(records[len(records)-1], false)
+	}
+	if (backward && hasMore) || (!backward && (c != nil || params.Offset > 0)) {
+		page.PrevCursor = cursorOf(records[0], true)
 	}
-	return nil
+	return page, nil
+}
+
+// DeleteOptions holds the options for deleting a news.
+type DeleteOptions struct {
+	// Version, when set, only deletes the news if its current version
+	// matches.
+	Version int
+	// Idempotent treats deleting an unknown or already deleted news as a
+	// success instead of a not found error.
+	Idempotent bool
+}
+
+// DeleteByID deletes a news by its ID.
+func (s Store) DeleteByID(ctx context.Context, id uuid.UUID, opts DeleteOptions) error {
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		old, err := NewStore(tx).findForUpdate(ctx, id, opts.Version)
+		if err != nil {
+			var dbErr *CustomError
+			if opts.Idempotent && errors.As(err, &dbErr) && dbErr.HTTPStatusCode() == http.StatusNotFound {
+				return nil
+			}
+			return err
+		}
+		if _, err := tx.NewDelete().Model(old).WherePK().Exec(ctx); err != nil {
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		changes := map[string]Change{"deleted_at": {New: time.Now().UTC()}}
+		return NewStore(tx).audit(ctx, newAuditEntry(ctx, AuditDelete, id, changes))
+	})
+}
+
+// UpdateByID update news by it's ID. When the version of the news is set,
+// the update only succeeds if it matches the stored version. On success
+// the news holds the new version, of which a revision is stored.
+func (s Store) UpdateByID(ctx context.Context, id uuid.UUID, news *Record) error {
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		old, err := NewStore(tx).findForUpdate(ctx, id, news.Version)
+		if err != nil {
+			return err
+		}
+
+		news.ID = id
+		news.UpdatedAt = time.Now()
+		news.CreatedBy = old.CreatedBy
+		news.UpdatedBy = actor(ctx)
+		news.Status = old.Status
+		_, err = tx.NewUpdate().
+			Model(news).
+			ExcludeColumn("created_by", "status").
+			Value("version", "version + 1").
+			WherePK().
+			Returning("version").
+			Exec(ctx)
+		if err != nil {
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		if err := NewStore(tx).snapshot(ctx, news); err != nil {
+			return err
+		}
+		return NewStore(tx).audit(ctx, newAuditEntry(ctx, AuditUpdate, id, diff(old, news)))
+	})
+}
+
+// findForUpdate locks the news for the rest of the transaction. A non zero
+// version must match the stored version.
+func (s Store) findForUpdate(ctx context.Context, id uuid.UUID, version int) (*Record, error) {
+	var news Record
+	if err := s.db.NewSelect().Model(&news).Where("id = ?", id).For("UPDATE").Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewCustomError(err, http.StatusNotFound).WithCode(CodeNotFound)
+		}
+		return nil, NewCustomError(err, http.StatusInternalServerError)
+	}
+	if version > 0 && version != news.Version {
+		return nil, NewCustomError(ErrVersionMismatch, http.StatusPreconditionFailed).WithCode(CodeVersionMismatch)
+	}
+	return &news, nil
 }
@@ -2,20 +2,19 @@ package news_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/prashsamosa/newsapi/internal/auth"
 	"github.com/prashsamosa/newsapi/internal/news"
-	"github.com/prashsamosa/newsapi/internal/postgres"
-	"github.com/docker/go-connections/nat"
+	"github.com/prashsamosa/newsapi/internal/postgres/postgrestest"
+	"github.com/prashsamosa/newsapi/internal/requestid"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
-	"github.com/testcontainers/testcontainers-go"
-	pgtc "github.com/testcontainers/testcontainers-go/modules/postgres"
-	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/uptrace/bun"
 )
 
@@ -23,7 +22,11 @@ var db *bun.DB
 
 func TestMain(m *testing.M) {
 	ctx := context.Background()
-	pdb, cf, err := createTestDB(ctx)
+	pdb, cf, err := postgrestest.NewDB(ctx, "testdata/sql/store.sql")
+	if errors.Is(err, postgrestest.ErrUnavailable) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(m.Run())
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -39,6 +42,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestStore_Create(t *testing.T) {
+	postgrestest.RequireDB(t, db)
 	testCases := []struct {
 		name               string
 		news               *news.Record
@@ -84,7 +88,8 @@ func TestStore_Create(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assertOnNews(t, tc.news, createdNews)
-				err = s.DeleteByID(context.Background(), createdNews.ID)
+				assert.Equal(t, 1, createdNews.Version)
+				err = s.DeleteByID(context.Background(), createdNews.ID, news.DeleteOptions{})
 				assert.NoError(t, err)
 			}
 		})
@@ -92,6 +97,7 @@ func TestStore_Create(t *testing.T) {
 }
 
 func TestStore_FindByID(t *testing.T) {
+	postgrestest.RequireDB(t, db)
 	testCases := []struct {
 		name               string
 		id                 uuid.UUID
@@ -146,6 +152,7 @@ func TestStore_FindByID(t *testing.T) {
 }
 
 func TestStore_FindAll(t *testing.T) {
+	postgrestest.RequireDB(t, db)
 	testCases := []struct {
 		name         string
 		expectedNews []*news.Record
@@ -154,7 +161,7 @@ func TestStore_FindAll(t *testing.T) {
 			name: "found all",
 			expectedNews: []*news.Record{
 				{
-					Author:  "Batman",
+					Author:  "Superman",
 					Title:   "Breaking News",
 					Summary: "A brief summary of the news",
 					Content: "Full content of the news article",
@@ -162,7 +169,7 @@ func TestStore_FindAll(t *testing.T) {
 					Tags:    []string{"tag1", "tag2"},
 				},
 				{
-					Author:  "Superman",
+					Author:  "Batman",
 					Title:   "Breaking News",
 					Summary: "A brief summary of the news",
 					Content: "Full content of the news article",
@@ -185,18 +192,220 @@ func TestStore_FindAll(t *testing.T) {
 	}
 }
 
+func TestStore_FindPage(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := news.NewStore(db)
+	ctx := context.Background()
+
+	first, err := s.FindPage(ctx, news.ListParams{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, first.Records, 1)
+	assert.Equal(t, 2, first.TotalHint)
+	assert.NotNil(t, first.NextCursor)
+	assert.Nil(t, first.PrevCursor)
+
+	second, err := s.FindPage(ctx, news.ListParams{Limit: 1, Cursor: first.NextCursor})
+	assert.NoError(t, err)
+	assert.Len(t, second.Records, 1)
+	assert.NotEqual(t, first.Records[0].ID, second.Records[0].ID)
+	assert.Nil(t, second.NextCursor)
+	assert.NotNil(t, second.PrevCursor)
+
+	back, err := s.FindPage(ctx, news.ListParams{Limit: 1, Cursor: second.PrevCursor})
+	assert.NoError(t, err)
+	assert.Len(t, back.Records, 1)
+	assert.Equal(t, first.Records[0].ID, back.Records[0].ID)
+	assert.NotNil(t, back.NextCursor)
+	assert.Nil(t, back.PrevCursor)
+
+	offset, err := s.FindPage(ctx, news.ListParams{Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Len(t, offset.Records, 1)
+	assert.Equal(t, second.Records[0].ID, offset.Records[0].ID)
+	assert.NotNil(t, offset.PrevCursor)
+}
+
+func TestStore_FindPage_Filter(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	testCases := []struct {
+		name          string
+		filter        news.Filter
+		expectedCount int
+	}{
+		{
+			name:          "author",
+			filter:        news.Filter{Author: "Superman"},
+			expectedCount: 1,
+		},
+		{
+			name:          "any tag",
+			filter:        news.Filter{Tags: []string{"tag1", "Superhero"}},
+			expectedCount: 2,
+		},
+		{
+			name:          "all tags",
+			filter:        news.Filter{Tags: []string{"tag1", "Superhero"}, TagMatch: news.TagMatchAll},
+			expectedCount: 0,
+		},
+		{
+			name:          "source host",
+			filter:        news.Filter{SourceHost: "WWW.EXAMPLE.COM"},
+			expectedCount: 2,
+		},
+		{
+			name:          "created before",
+			filter:        news.Filter{CreatedBefore: time.Now().Add(-24 * time.Hour)},
+			expectedCount: 0,
+		},
+		{
+			name:          "updated since",
+			filter:        news.Filter{UpdatedSince: time.Now().Add(-24 * time.Hour)},
+			expectedCount: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := news.NewStore(db)
+
+			page, err := s.FindPage(context.Background(), news.ListParams{Limit: 10, Filter: tc.filter})
+
+			assert.NoError(t, err)
+			assert.Len(t, page.Records, tc.expectedCount)
+			assert.Equal(t, tc.expectedCount, page.TotalHint)
+		})
+	}
+}
+
+func TestStore_FindPage_Sort(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	testCases := []struct {
+		name            string
+		sort            string
+		expectedAuthors []string
+	}{
+		{
+			name:            "default newest first",
+			expectedAuthors: []string{"Superman", "Batman"},
+		},
+		{
+			name:            "oldest first",
+			sort:            "created_at",
+			expectedAuthors: []string{"Batman", "Superman"},
+		},
+		{
+			name:            "author descending",
+			sort:            "-author",
+			expectedAuthors: []string{"Superman", "Batman"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := news.NewStore(db)
+			params := news.ListParams{Limit: 10}
+			if tc.sort != "" {
+				sort, err := news.ParseSort(tc.sort)
+				assert.NoError(t, err)
+				params.Sort = sort
+			}
+
+			page, err := s.FindPage(context.Background(), params)
+
+			assert.NoError(t, err)
+			authors := make([]string, 0, len(page.Records))
+			for _, n := range page.Records {
+				authors = append(authors, n.Author)
+			}
+			assert.Equal(t, tc.expectedAuthors, authors)
+		})
+	}
+}
+
+func TestStore_Search(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	testCases := []struct {
+		name          string
+		query         string
+		expectedCount int
+	}{
+		{
+			name:          "match title",
+			query:         "breaking",
+			expectedCount: 2,
+		},
+		{
+			name:          "match content",
+			query:         "article",
+			expectedCount: 2,
+		},
+		{
+			name:          "no match",
+			query:         "weather",
+			expectedCount: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := news.NewStore(db)
+
+			results, err := s.Search(context.Background(), news.SearchParams{Query: tc.query, Limit: 10})
+
+			assert.NoError(t, err)
+			assert.Len(t, results, tc.expectedCount)
+			for _, r := range results {
+				assert.Greater(t, r.Rank, 0.0)
+				assert.Contains(t, r.TitleHighlight+r.SummaryHighlight+r.ContentHighlight, "<mark>")
+			}
+		})
+	}
+}
+
 func TestStore_DeleteByID(t *testing.T) {
+	postgrestest.RequireDB(t, db)
 	testCases := []struct {
-		name string
-		id   uuid.UUID
+		name           string
+		id             uuid.UUID
+		opts           news.DeleteOptions
+		expectedStatus int
 	}{
+		{
+			name:           "stale version",
+			id:             uuid.MustParse("17628bea-9d11-47f9-986e-16703a87e451"),
+			opts:           news.DeleteOptions{Version: 7},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
 		{
 			name: "deleted",
 			id:   uuid.MustParse("17628bea-9d11-47f9-986e-16703a87e451"),
+			opts: news.DeleteOptions{Version: 1},
 		},
 		{
-			name: "not found",
+			name:           "already deleted",
+			id:             uuid.MustParse("17628bea-9d11-47f9-986e-16703a87e451"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "already deleted with version",
+			id:             uuid.MustParse("f710bc79-9ad3-4e0f-8dab-e43d94b42fbb"),
+			opts:           news.DeleteOptions{Version: 1},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "not found",
+			id:             uuid.MustParse("6a3483c7-e28e-442e-b603-b06ff60eeeb4"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "idempotent already deleted",
 			id:   uuid.MustParse("f710bc79-9ad3-4e0f-8dab-e43d94b42fbb"),
+			opts: news.DeleteOptions{Idempotent: true},
+		},
+		{
+			name: "idempotent not found",
+			id:   uuid.MustParse("6a3483c7-e28e-442e-b603-b06ff60eeeb4"),
+			opts: news.DeleteOptions{Idempotent: true, Version: 3},
 		},
 	}
 
@@ -204,17 +413,27 @@ func TestStore_DeleteByID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			s := news.NewStore(db)
 
-			err := s.DeleteByID(context.Background(), tc.id)
-			assert.NoError(t, err)
+			err := s.DeleteByID(context.Background(), tc.id, tc.opts)
+
+			if tc.expectedStatus != 0 {
+				assert.Error(t, err)
+				var storeErr *news.CustomError
+				assert.ErrorAs(t, err, &storeErr)
+				assert.Equal(t, tc.expectedStatus, storeErr.HTTPStatusCode())
+			} else {
+				assert.NoError(t, err)
+			}
 		})
 	}
 }
 
 func TestStore_UpdatedByID(t *testing.T) {
+	postgrestest.RequireDB(t, db)
 	testCases := []struct {
-		name           string
-		news           *news.Record
-		expectedStatus int
+		name            string
+		news            *news.Record
+		expectedStatus  int
+		expectedVersion int
 	}{
 		{
 			name: "updated",
@@ -229,6 +448,37 @@ func TestStore_UpdatedByID(t *testing.T) {
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 			},
+			expectedVersion: 2,
+		},
+		{
+			name: "updated with matching version",
+			news: &news.Record{
+				ID:        uuid.MustParse("bde0c593-0df6-4eba-9326-3f00be67aade"),
+				Author:    "Wolverine",
+				Title:     "Breaking News",
+				Summary:   "A brief summary of the news",
+				Content:   "Full content of the news article",
+				Source:    "https://www.example.com",
+				Tags:      []string{"tag1", "tag2"},
+				CreatedAt: time.Now(),
+				Version:   2,
+			},
+			expectedVersion: 3,
+		},
+		{
+			name: "stale version",
+			news: &news.Record{
+				ID:        uuid.MustParse("bde0c593-0df6-4eba-9326-3f00be67aade"),
+				Author:    "Wolverine",
+				Title:     "Breaking News",
+				Summary:   "A brief summary of the news",
+				Content:   "Full content of the news article",
+				Source:    "https://www.example.com",
+				Tags:      []string{"tag1", "tag2"},
+				CreatedAt: time.Now(),
+				Version:   1,
+			},
+			expectedStatus: http.StatusPreconditionFailed,
 		},
 		{
 			name: "not found",
@@ -252,86 +502,515 @@ func TestStore_UpdatedByID(t *testing.T) {
 				assert.Equal(t, tc.expectedStatus, storeErr.HTTPStatusCode())
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedVersion, tc.news.Version)
 			}
 		})
 	}
 }
 
-func assertOnNews(tb testing.TB, expected, got *news.Record) {
-	tb.Helper()
-	assert.Equal(tb, expected.Author, got.Author)
-	assert.Equal(tb, expected.Title, got.Title)
-	assert.Equal(tb, expected.Content, got.Content)
-	assert.Equal(tb, expected.Summary, got.Summary)
-	assert.Equal(tb, expected.Source, got.Source)
-	assert.Equal(tb, expected.Tags, got.Tags)
-	assert.NotEqual(tb, time.Time{}, got.CreatedAt)
-	assert.NotEqual(tb, time.Time{}, got.UpdatedAt)
-	assert.Equal(tb, time.Time{}, got.DeletedAt)
+func TestStore_Trash(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := news.NewStore(db)
+	ctx := context.Background()
+	batman := uuid.MustParse("17628bea-9d11-47f9-986e-16703a87e451")
+	spiderman := uuid.MustParse("f710bc79-9ad3-4e0f-8dab-e43d94b42fbb")
+	superman := uuid.MustParse("bde0c593-0df6-4eba-9326-3f00be67aade")
+
+	t.Run("find trash", func(t *testing.T) {
+		page, err := s.FindTrash(ctx, 10, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, page.TotalHint)
+		if assert.Len(t, page.Records, 2) {
+			assert.Equal(t, batman, page.Records[0].ID)
+			assert.Equal(t, spiderman, page.Records[1].ID)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		n, err := s.RestoreByID(ctx, batman)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Batman", n.Author)
+		assert.Equal(t, 2, n.Version)
+		assert.Equal(t, time.Time{}, n.DeletedAt)
+		_, err = s.FindByID(ctx, batman)
+		assert.NoError(t, err)
+		entries, _, err := s.FindAudit(ctx, news.AuditFilter{NewsID: batman, Action: news.AuditRestore, Limit: 10})
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 1) {
+			deletedAt, err := time.Parse(time.RFC3339Nano, entries[0].Changes["deleted_at"].Old.(string))
+			assert.NoError(t, err)
+			assert.WithinDuration(t, time.Now(), deletedAt, time.Hour)
+		}
+	})
+
+	t.Run("restore not in trash", func(t *testing.T) {
+		_, err := s.RestoreByID(ctx, superman)
+
+		var storeErr *news.CustomError
+		assert.ErrorAs(t, err, &storeErr)
+		assert.Equal(t, http.StatusNotFound, storeErr.HTTPStatusCode())
+	})
+
+	t.Run("restore unknown id", func(t *testing.T) {
+		_, err := s.RestoreByID(ctx, uuid.New())
+
+		var storeErr *news.CustomError
+		assert.ErrorAs(t, err, &storeErr)
+		assert.Equal(t, http.StatusNotFound, storeErr.HTTPStatusCode())
+	})
+
+	t.Run("purge trashed before", func(t *testing.T) {
+		n, err := s.PurgeTrashedBefore(ctx, time.Now().Add(-time.Hour))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		err := s.PurgeByID(ctx, spiderman)
+
+		assert.NoError(t, err)
+		page, err := s.FindTrash(ctx, 10, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, page.Records)
+	})
+
+	t.Run("purge not found", func(t *testing.T) {
+		err := s.PurgeByID(ctx, spiderman)
+
+		var storeErr *news.CustomError
+		assert.ErrorAs(t, err, &storeErr)
+		assert.Equal(t, http.StatusNotFound, storeErr.HTTPStatusCode())
+	})
 }
 
-func createTestContainer(ctx context.Context) (ctr *pgtc.PostgresContainer, err error) {
-	wd, err := os.Getwd()
-	if err != nil {
-		return ctr, fmt.Errorf("working dir: %w", err)
-	}
+func TestStore_Audit(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := news.NewStore(db)
+	ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "apikey:auditor"})
+	ctx = requestid.NewContext(ctx, "req-audit")
+
+	created, err := s.Create(ctx, &news.Record{
+		Author:  "test-author",
+		Title:   "test-title",
+		Summary: "test-summary",
+		Content: "test-content",
+		Source:  "https://www.example.com",
+		Tags:    []string{"tag1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "apikey:auditor", created.CreatedBy)
+	update := *created
+	update.Title = "updated-title"
+	assert.NoError(t, s.UpdateByID(ctx, created.ID, &update))
+	assert.NoError(t, s.DeleteByID(ctx, created.ID, news.DeleteOptions{}))
 
-	sqlScripts := wd + "/testdata/sql/store.sql"
-
-	ctr, err = pgtc.Run(
-		ctx,
-		"postgres:16-alpine",
-		pgtc.WithInitScripts(sqlScripts),
-		pgtc.WithDatabase("postgres"),
-		pgtc.WithUsername("postgres"),
-		pgtc.WithPassword("postgres"),
-		testcontainers.WithWaitStrategy(
-			wait.ForLog("database system is ready to accept connections").
-				WithOccurrence(2).
-				WithStartupTimeout(30*time.Second),
-		),
-	)
-	if err != nil {
-		return ctr, fmt.Errorf("run container: %w", err)
-	}
-	return ctr, nil
+	t.Run("news audit", func(t *testing.T) {
+		entries, total, err := s.FindAudit(ctx, news.AuditFilter{NewsID: created.ID, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		if assert.Len(t, entries, 3) {
+			assert.Equal(t, news.AuditCreate, entries[0].Action)
+			assert.Equal(t, news.AuditUpdate, entries[1].Action)
+			assert.Equal(t, news.AuditDelete, entries[2].Action)
+			assert.Equal(t, map[string]news.Change{"title": {Old: "test-title", New: "updated-title"}}, entries[1].Changes)
+			for _, e := range entries {
+				assert.Equal(t, "apikey:auditor", e.Actor)
+				assert.Equal(t, "req-audit", e.RequestID)
+			}
+		}
+	})
+
+	t.Run("filter by action", func(t *testing.T) {
+		entries, _, err := s.FindAudit(ctx, news.AuditFilter{Actor: "apikey:auditor", Action: news.AuditUpdate, Limit: 10})
+
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, created.ID, entries[0].NewsID)
+		}
+	})
+
+	t.Run("schedule only update", func(t *testing.T) {
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "apikey:scheduler"})
+		scheduled, err := s.Create(ctx, &news.Record{
+			Author:  "test-author",
+			Title:   "scheduled-title",
+			Summary: "test-summary",
+			Content: "test-content",
+			Source:  "https://www.example.com",
+			Tags:    []string{"tag1"},
+		})
+		assert.NoError(t, err)
+		update := *scheduled
+		update.PublishAt = time.Now().Add(time.Hour)
+		assert.NoError(t, s.UpdateByID(ctx, scheduled.ID, &update))
+
+		entries, _, err := s.FindAudit(ctx, news.AuditFilter{NewsID: scheduled.ID, Action: news.AuditUpdate, Limit: 10})
+
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Contains(t, entries[0].Changes, "publish_at")
+			assert.NotContains(t, entries[0].Changes, "expire_at")
+			assert.Nil(t, entries[0].Changes["publish_at"].Old)
+			assert.NotNil(t, entries[0].Changes["publish_at"].New)
+		}
+	})
+
+	t.Run("append only", func(t *testing.T) {
+		_, err := db.NewRaw("UPDATE news_audit SET actor = 'someone-else'").Exec(ctx)
+
+		assert.ErrorContains(t, err, "append-only")
+	})
 }
 
-type DBCleanupFunc func(ctx context.Context) error
+func TestStore_Revisions(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := news.NewStore(db)
+	ctx := context.Background()
 
-func createTestDB(ctx context.Context) (*bun.DB, DBCleanupFunc, error) {
-	ctr, err := createTestContainer(ctx)
-	if err != nil {
-		return nil, nil, fmt.Errorf("create test container: %w", err)
-	}
+	created, err := s.Create(ctx, &news.Record{
+		Author:  "test-author",
+		Title:   "first-title",
+		Summary: "test-summary",
+		Content: "test-content",
+		Source:  "https://www.example.com",
+		Tags:    []string{"tag1"},
+	})
+	assert.NoError(t, err)
+	update := *created
+	update.Title = "second-title"
+	update.PublishAt = time.Now().Add(time.Hour)
+	update.ExpireAt = time.Now().Add(24 * time.Hour)
+	assert.NoError(t, s.UpdateByID(ctx, created.ID, &update))
 
-	p, err := ctr.MappedPort(ctx, nat.Port("5432/tcp"))
-	if err != nil {
-		return nil, nil, fmt.Errorf("mapped port: %w", err)
-	}
+	t.Run("find revisions", func(t *testing.T) {
+		revisions, total, err := s.FindRevisions(ctx, created.ID, 10, 0)
 
-	db, err := postgres.NewDB(&postgres.Config{
-		Host:     "localhost",
-		Debug:    true,
-		DBName:   "postgres",
-		User:     "postgres",
-		Password: "postgres",
-		Port:     p.Port(),
-		SSLMode:  "disable",
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		if assert.Len(t, revisions, 2) {
+			assert.Equal(t, 2, revisions[0].Revision)
+			assert.Equal(t, "second-title", revisions[0].Title)
+			assert.Equal(t, 1, revisions[1].Revision)
+			assert.Equal(t, "first-title", revisions[1].Title)
+		}
 	})
-	if err != nil {
-		return nil, nil, fmt.Errorf("new db: %w", err)
+
+	t.Run("find revisions of unknown news", func(t *testing.T) {
+		_, _, err := s.FindRevisions(ctx, uuid.New(), 10, 0)
+
+		var storeErr *news.CustomError
+		assert.ErrorAs(t, err, &storeErr)
+		assert.Equal(t, http.StatusNotFound, storeErr.HTTPStatusCode())
+	})
+
+	t.Run("find unknown revision", func(t *testing.T) {
+		_, err := s.FindRevision(ctx, created.ID, 10)
+
+		var storeErr *news.CustomError
+		assert.ErrorAs(t, err, &storeErr)
+		assert.Equal(t, http.StatusNotFound, storeErr.HTTPStatusCode())
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		changes, err := s.DiffRevisions(ctx, created.ID, 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]news.Change{"title": {Old: "first-title", New: "second-title"}}, changes)
+	})
+
+	t.Run("restore stale version", func(t *testing.T) {
+		_, err := s.RestoreRevision(ctx, created.ID, 1, 1)
+
+		var storeErr *news.CustomError
+		assert.ErrorAs(t, err, &storeErr)
+		assert.Equal(t, http.StatusPreconditionFailed, storeErr.HTTPStatusCode())
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		restored, err := s.RestoreRevision(ctx, created.ID, 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "first-title", restored.Title)
+		assert.Equal(t, 3, restored.Version)
+		n, err := s.FindByID(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, update.PublishAt, n.PublishAt, time.Millisecond)
+		assert.WithinDuration(t, update.ExpireAt, n.ExpireAt, time.Millisecond)
+		rev, err := s.FindRevision(ctx, created.ID, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, "first-title", rev.Title)
+	})
+}
+
+func TestStore_Transition(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := news.NewStore(db)
+	ctx := context.Background()
+
+	created, err := s.Create(ctx, &news.Record{
+		Author:  "test-author",
+		Title:   "workflow-title",
+		Summary: "test-summary",
+		Content: "test-content",
+		Source:  "https://www.example.com",
+		Tags:    []string{"tag1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, news.StatusDraft, created.Status)
+
+	t.Run("skip review", func(t *testing.T) {
+		_, err := s.Transition(ctx, created.ID, news.StatusPublished, 0)
+
+		var storeErr *news.CustomError
+		assert.ErrorAs(t, err, &storeErr)
+		assert.Equal(t, http.StatusConflict, storeErr.HTTPStatusCode())
+		assert.ErrorIs(t, err, news.ErrInvalidTransition)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		_, err := s.Transition(ctx, created.ID, news.StatusReview, 5)
+
+		var storeErr *news.CustomError
+		assert.ErrorAs(t, err, &storeErr)
+		assert.Equal(t, http.StatusPreconditionFailed, storeErr.HTTPStatusCode())
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		n, err := s.Transition(ctx, created.ID, news.StatusReview, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n.Version)
+		n, err = s.Transition(ctx, created.ID, news.StatusPublished, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, news.StatusPublished, n.Status)
+		assert.Equal(t, 3, n.Version)
+		page, err := s.FindPage(ctx, news.ListParams{
+			Limit:  10,
+			Filter: news.Filter{Author: "test-author", Statuses: []news.Status{news.StatusPublished}},
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, page.Records, 1) {
+			assert.Equal(t, created.ID, page.Records[0].ID)
+		}
+	})
+
+	t.Run("transitions are snapshotted", func(t *testing.T) {
+		n, err := s.FindByID(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n.Version)
+
+		revisions, total, err := s.FindRevisions(ctx, created.ID, 10, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		if assert.Len(t, revisions, 3) {
+			assert.Equal(t, 3, revisions[0].Revision)
+			assert.Equal(t, 2, revisions[1].Revision)
+		}
+	})
+
+	t.Run("update keeps the status", func(t *testing.T) {
+		update := *created
+		update.Version = 0
+		update.Status = news.StatusDraft
+		assert.NoError(t, s.UpdateByID(ctx, created.ID, &update))
+
+		n, err := s.FindByID(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, news.StatusPublished, n.Status)
+	})
+
+	t.Run("back to draft", func(t *testing.T) {
+		n, err := s.Transition(ctx, created.ID, news.StatusDraft, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, news.StatusDraft, n.Status)
+	})
+}
+
+func TestStore_Schedule(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	s := news.NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	create := func(t *testing.T, title string, publishAt, expireAt time.Time) *news.Record {
+		t.Helper()
+		n, err := s.Create(ctx, &news.Record{
+			Author:    "scheduled-author",
+			Title:     title,
+			Summary:   "test-summary",
+			Content:   "test-content",
+			Source:    "https://www.example.com",
+			Tags:      []string{"tag1"},
+			PublishAt: publishAt,
+			ExpireAt:  expireAt,
+		})
+		assert.NoError(t, err)
+		_, err = s.Transition(ctx, n.ID, news.StatusReview, 0)
+		assert.NoError(t, err)
+		return n
 	}
+	due := create(t, "due", now.Add(-time.Minute), now.Add(time.Hour))
+	later := create(t, "later", now.Add(time.Hour), time.Time{})
+
+	t.Run("publish due", func(t *testing.T) {
+		n, err := s.PublishDue(ctx, now)
 
-	cf := func(ctx context.Context) error {
-		if err := db.Close(); err != nil {
-			return fmt.Errorf("db close: %w", err)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		published, err := s.FindByID(ctx, due.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, news.StatusPublished, published.Status)
+		scheduled, err := s.FindByID(ctx, later.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, news.StatusReview, scheduled.Status)
+	})
+
+	t.Run("live listing", func(t *testing.T) {
+		page, err := s.FindPage(ctx, news.ListParams{
+			Limit:  10,
+			Filter: news.Filter{Author: "scheduled-author", LiveAt: now},
+		})
+
+		assert.NoError(t, err)
+		if assert.Len(t, page.Records, 1) {
+			assert.Equal(t, due.ID, page.Records[0].ID)
 		}
-		if err := ctr.Terminate(ctx); err != nil {
-			return fmt.Errorf("container terminate: %w", err)
+		page, err = s.FindPage(ctx, news.ListParams{
+			Limit:  10,
+			Filter: news.Filter{Author: "scheduled-author", LiveAt: now.Add(2 * time.Hour)},
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, page.Records)
+	})
+
+	t.Run("expire due", func(t *testing.T) {
+		n, err := s.ExpireDue(ctx, now.Add(2*time.Hour))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		archived, err := s.FindByID(ctx, due.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, news.StatusArchived, archived.Status)
+	})
+}
+
+func TestStore_Batch(t *testing.T) {
+	postgrestest.RequireDB(t, db)
+	batman := uuid.MustParse("17628bea-9d11-47f9-986e-16703a87e451")
+	superman := uuid.MustParse("bde0c593-0df6-4eba-9326-3f00be67aade")
+	newRecord := func(author string) *news.Record {
+		return &news.Record{
+			Author:    author,
+			Title:     "Batch News",
+			Summary:   "A brief summary of the news",
+			Content:   "Full content of the news article",
+			Source:    "https://www.example.com",
+			Tags:      []string{"batch"},
+			CreatedAt: time.Now(),
 		}
-		return nil
 	}
 
-	return db, cf, nil
+	testCases := []struct {
+		name             string
+		ops              []news.BatchOperation
+		atomic           bool
+		expectedStatuses []int
+	}{
+		{
+			name: "per item",
+			ops: []news.BatchOperation{
+				{Op: news.BatchCreate, Record: newRecord("Flash")},
+				{Op: news.BatchCreate, Record: newRecord("Aquaman")},
+				{Op: news.BatchUpdate, ID: uuid.MustParse("6a3483c7-e28e-442e-b603-b06ff60eeeb4"), Record: newRecord("Nobody")},
+				{Op: news.BatchDelete, ID: batman, Version: 2},
+			},
+			expectedStatuses: []int{0, 0, http.StatusNotFound, 0},
+		},
+		{
+			name: "per item with a failed create",
+			ops: []news.BatchOperation{
+				{Op: news.BatchCreate, Record: newRecord("")},
+				{Op: news.BatchCreate, Record: newRecord("Cyborg")},
+			},
+			expectedStatuses: []int{http.StatusInternalServerError, 0},
+		},
+		{
+			name: "atomic with a failed update",
+			ops: []news.BatchOperation{
+				{Op: news.BatchCreate, Record: newRecord("Robin")},
+				{Op: news.BatchUpdate, ID: superman, Version: 1, Record: newRecord("Superman")},
+			},
+			atomic:           true,
+			expectedStatuses: []int{http.StatusFailedDependency, http.StatusPreconditionFailed},
+		},
+		{
+			name: "atomic with a failed create",
+			ops: []news.BatchOperation{
+				{Op: news.BatchCreate, Record: newRecord("Batgirl")},
+				{Op: news.BatchCreate, Record: newRecord("")},
+			},
+			atomic:           true,
+			expectedStatuses: []int{http.StatusFailedDependency, http.StatusInternalServerError},
+		},
+		{
+			name: "atomic",
+			ops: []news.BatchOperation{
+				{Op: news.BatchCreate, Record: newRecord("Nightwing")},
+				{Op: news.BatchUpdate, ID: superman, Version: 3, Record: newRecord("Superman")},
+			},
+			atomic:           true,
+			expectedStatuses: []int{0, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := news.NewStore(db)
+
+			results, err := s.Batch(context.Background(), tc.ops, tc.atomic)
+
+			assert.NoError(t, err)
+			if !assert.Len(t, results, len(tc.ops)) {
+				return
+			}
+			for i, res := range results {
+				if tc.expectedStatuses[i] != 0 {
+					var storeErr *news.CustomError
+					assert.ErrorAs(t, res.Err, &storeErr)
+					assert.Equal(t, tc.expectedStatuses[i], storeErr.HTTPStatusCode())
+					if tc.ops[i].Op == news.BatchCreate && tc.ops[i].Record.Author != "" {
+						_, err := s.FindByID(context.Background(), tc.ops[i].Record.ID)
+						assert.Error(t, err, "rolled back news must not be created")
+					}
+					continue
+				}
+				assert.NoError(t, res.Err)
+				if tc.ops[i].Op == news.BatchDelete {
+					continue
+				}
+				got, err := s.FindByID(context.Background(), res.Record.ID)
+				assert.NoError(t, err)
+				assertOnNews(t, tc.ops[i].Record, got)
+			}
+		})
+	}
+}
+
+func assertOnNews(tb testing.TB, expected, got *news.Record) {
+	tb.Helper()
+	assert.Equal(tb, expected.Author, got.Author)
+	assert.Equal(tb, expected.Title, got.Title)
+	assert.Equal(tb, expected.Content, got.Content)
+	assert.Equal(tb, expected.Summary, got.Summary)
+	assert.Equal(tb, expected.Source, got.Source)
+	assert.Equal(tb, expected.Tags, got.Tags)
+	assert.NotEqual(tb, time.Time{}, got.CreatedAt)
+	assert.NotEqual(tb, time.Time{}, got.UpdatedAt)
+	assert.Equal(tb, time.Time{}, got.DeletedAt)
 }
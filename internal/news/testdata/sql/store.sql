@@ -1,18 +1,3 @@
-CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-
-CREATE TABLE IF NOT EXISTS news (
-  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-  author TEXT NOT NULL,
-  title TEXT NOT NULL,
-  summary TEXT NOT NULL,
-  content TEXT NOT NULL,
-  source TEXT NOT NULL,
-  tags TEXT[] NOT NULL,
-  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
-  deleted_at TIMESTAMP WITH TIME ZONE,
-  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
-);
-
 INSERT INTO news (id, author, title, summary, content, source, tags, created_at, updated_at)
 VALUES (
   '17628bea-9d11-47f9-986e-16703a87e451',
@@ -0,0 +1,127 @@
+package news
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// FindTrash returns a page of soft deleted news, most recently deleted
+// first.
+func (s Store) FindTrash(ctx context.Context, limit, offset int) (*Page, error) {
+	var records []*Record
+	total, err := s.db.NewSelect().
+		Model(&records).
+		WhereDeleted().
+		OrderExpr("deleted_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		ScanAndCount(ctx)
+	if err != nil {
+		return nil, NewCustomError(err, http.StatusInternalServerError)
+	}
+	return &Page{Records: records, TotalHint: total}, nil
+}
+
+// RestoreByID moves a soft deleted news out of the trash. The restore
+// counts as an update and bumps the version.
+func (s Store) RestoreByID(ctx context.Context, id uuid.UUID) (*Record, error) {
+	var news Record
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// The news is locked first, so that the audit log records when it
+		// was deleted.
+		var trashed Record
+		err := tx.NewSelect().
+			Model(&trashed).
+			WhereDeleted().
+			Where("id = ?", id).
+			For("UPDATE").
+			Scan(ctx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return NewCustomError(err, http.StatusNotFound).WithCode(CodeNotFound)
+			}
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+
+		_, err = tx.NewUpdate().
+			Model(&news).
+			WhereDeleted().
+			Set("deleted_at = NULL").
+			Set("updated_at = current_timestamp").
+			Set("updated_by = ?", bun.NullZero(actor(ctx))).
+			Set("version = version + 1").
+			Where("id = ?", id).
+			Returning("?TableColumns").
+			Exec(ctx)
+		if err != nil {
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		if err := NewStore(tx).snapshot(ctx, &news); err != nil {
+			return err
+		}
+		changes := map[string]Change{"deleted_at": {Old: trashed.DeletedAt.UTC()}}
+		return NewStore(tx).audit(ctx, newAuditEntry(ctx, AuditRestore, id, changes))
+	})
+	if err != nil {
+		return nil, err
+	}
+	news.DeletedAt = time.Time{}
+	return &news, nil
+}
+
+// PurgeByID permanently deletes a news, whether it is in the trash or not.
+// The audit log keeps its last state.
+func (s Store) PurgeByID(ctx context.Context, id uuid.UUID) error {
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var news Record
+		err := tx.NewSelect().
+			Model(&news).
+			WhereAllWithDeleted().
+			Where("id = ?", id).
+			For("UPDATE").
+			Scan(ctx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return NewCustomError(err, http.StatusNotFound).WithCode(CodeNotFound)
+			}
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		if _, err := tx.NewDelete().Model(&news).WherePK().ForceDelete().Exec(ctx); err != nil {
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		return NewStore(tx).audit(ctx, newAuditEntry(ctx, AuditPurge, id, diff(&news, nil)))
+	})
+}
+
+// PurgeTrashedBefore permanently deletes the news moved to the trash before
+// the given time and returns how many were deleted.
+func (s Store) PurgeTrashedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var purged []*Record
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model(&purged).
+			WhereDeleted().
+			Where("deleted_at < ?", before).
+			ForceDelete().
+			Returning("?TableColumns").
+			Exec(ctx)
+		if err != nil {
+			return NewCustomError(err, http.StatusInternalServerError)
+		}
+		entries := make([]*AuditEntry, 0, len(purged))
+		for _, n := range purged {
+			entries = append(entries, newAuditEntry(ctx, AuditPurge, n.ID, diff(n, nil)))
+		}
+		return NewStore(tx).audit(ctx, entries...)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(purged)), nil
+}
@@ -0,0 +1,142 @@
+// Package postgrestest runs a PostgreSQL container with the migrations
+// applied, for the tests of the stores.
+package postgrestest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/prashsamosa/newsapi/internal/migration"
+	"github.com/prashsamosa/newsapi/internal/postgres"
+	"github.com/testcontainers/testcontainers-go"
+	pgtc "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+// ErrUnavailable is returned when no container can be started, so that the
+// tests needing the database are skipped.
+var ErrUnavailable = errors.New("docker is unavailable")
+
+// CleanupFunc closes the database and terminates its container.
+type CleanupFunc func(ctx context.Context) error
+
+// NewDB starts a PostgreSQL container, runs the migrations and then the SQL
+// files of the seeds, in order.
+func NewDB(ctx context.Context, seeds ...string) (*bun.DB, CleanupFunc, error) {
+	if err := checkDocker(ctx); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	ctr, err := pgtc.Run(
+		ctx,
+		"postgres:16-alpine",
+		pgtc.WithDatabase("postgres"),
+		pgtc.WithUsername("postgres"),
+		pgtc.WithPassword("postgres"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second),
+		),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("run container: %w", err)
+	}
+
+	p, err := ctr.MappedPort(ctx, nat.Port("5432/tcp"))
+	if err != nil {
+		return nil, nil, terminate(ctx, ctr, fmt.Errorf("mapped port: %w", err))
+	}
+
+	db, err := postgres.NewDB(&postgres.Config{
+		Host:     "localhost",
+		Debug:    true,
+		DBName:   "postgres",
+		User:     "postgres",
+		Password: "postgres",
+		Port:     p.Port(),
+		SSLMode:  "disable",
+	})
+	if err != nil {
+		return nil, nil, terminate(ctx, ctr, fmt.Errorf("new db: %w", err))
+	}
+
+	cf := func(ctx context.Context) error {
+		if err := db.Close(); err != nil {
+			return fmt.Errorf("db close: %w", err)
+		}
+		if err := ctr.Terminate(ctx); err != nil {
+			return fmt.Errorf("container terminate: %w", err)
+		}
+		return nil
+	}
+
+	if err := setup(ctx, db, seeds); err != nil {
+		if cerr := cf(ctx); cerr != nil {
+			return nil, nil, fmt.Errorf("%w, %w", err, cerr)
+		}
+		return nil, nil, err
+	}
+	return db, cf, nil
+}
+
+// setup runs the migrations and the seeds.
+func setup(ctx context.Context, db *bun.DB, seeds []string) error {
+	m := migrate.NewMigrator(db, migration.New(), migrate.WithMarkAppliedOnSuccess(true))
+	if err := m.Init(ctx); err != nil {
+		return fmt.Errorf("init migrations: %w", err)
+	}
+	if _, err := m.Migrate(ctx); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	for _, seed := range seeds {
+		query, err := os.ReadFile(seed)
+		if err != nil {
+			return fmt.Errorf("read seed: %w", err)
+		}
+		// The seeds bypass bun, which would take their ? as placeholders.
+		if _, err := db.DB.ExecContext(ctx, string(query)); err != nil {
+			return fmt.Errorf("seed %s: %w", seed, err)
+		}
+	}
+	return nil
+}
+
+func terminate(ctx context.Context, ctr *pgtc.PostgresContainer, err error) error {
+	if terr := ctr.Terminate(ctx); terr != nil {
+		return fmt.Errorf("%w, container terminate: %w", err, terr)
+	}
+	return err
+}
+
+// RequireDB skips the test when the database could not be started.
+func RequireDB(t testing.TB, db *bun.DB) {
+	t.Helper()
+	if db == nil {
+		t.Skip("no test database:", ErrUnavailable)
+	}
+}
+
+// checkDocker checks that the Docker daemon answers. The provider panics
+// when it finds no daemon at all.
+func checkDocker(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+	return provider.Health(ctx)
+}
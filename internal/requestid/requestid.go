@@ -0,0 +1,60 @@
+// Package requestid carries the ID of the request through the context.
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/logger"
+)
+
+// Header is the request header carrying the request ID.
+const Header = "X-Request-ID"
+
+// ctxKey for the request ID.
+type ctxKey struct{}
+
+// NewContext returns the context enriched with the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID of the context, empty if none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// MaxLength is the longest request ID accepted from the clients.
+const MaxLength = 128
+
+// Middleware identifies the request with the ID of the X-Request-ID header,
+// or a generated one when the header is missing or invalid. The ID is
+// echoed in the response and added to the request context and its logger.
+func Middleware(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !valid(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(Header, id)
+		ctx := NewContext(r.Context(), id)
+		ctx = logger.CtxWithLogger(ctx, logger.FromContext(ctx).With("requestId", id))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// valid reports whether the request ID is short enough and only holds
+// printable ASCII characters, so it is safe to log and echo.
+func valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package requestid_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prashsamosa/newsapi/internal/logger"
+	"github.com/prashsamosa/newsapi/internal/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Middleware(t *testing.T) {
+	testCases := []struct {
+		name       string
+		header     string
+		expectedID string
+	}{
+		{
+			name: "no header",
+		},
+		{
+			name:       "header",
+			header:     "req-1",
+			expectedID: "req-1",
+		},
+		{
+			name:   "header too long",
+			header: strings.Repeat("a", requestid.MaxLength+1),
+		},
+		{
+			name:   "header with spaces",
+			header: "req 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var logs bytes.Buffer
+			var id string
+			next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				id = requestid.FromContext(r.Context())
+				logger.FromContext(r.Context()).Error("failed")
+			})
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/news", http.NoBody)
+			r = r.WithContext(logger.CtxWithLogger(r.Context(), slog.New(slog.NewJSONHandler(&logs, nil))))
+			if tc.header != "" {
+				r.Header.Set(requestid.Header, tc.header)
+			}
+
+			// Act
+			requestid.Middleware(next)(w, r)
+
+			// Assert
+			if tc.expectedID != "" {
+				assert.Equal(t, tc.expectedID, id)
+			} else {
+				assert.NoError(t, uuid.Validate(id))
+			}
+			assert.Equal(t, id, w.Header().Get(requestid.Header))
+			assert.Contains(t, logs.String(), `"requestId":"`+id+`"`)
+		})
+	}
+}
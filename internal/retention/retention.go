@@ -0,0 +1,82 @@
+// Package retention periodically removes the records kept longer than a
+// retention period, like the news left in the trash.
+package retention
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/logger"
+)
+
+// Purger permanently deletes the records older than a given time and
+// returns how many were deleted.
+type Purger interface {
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PurgerFunc adapts a function to the Purger interface.
+type PurgerFunc func(ctx context.Context, before time.Time) (int64, error)
+
+// Purge calls f(ctx, before).
+func (f PurgerFunc) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return f(ctx, before)
+}
+
+// Job periodically purges the records older than the retention period.
+type Job struct {
+	name      string
+	purger    Purger
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// NewJob returns a job purging the records older than retention, once
+// every interval. The name identifies the job in the logs.
+func NewJob(name string, p Purger, retention, interval time.Duration) *Job {
+	return &Job{
+		name:      name,
+		purger:    p,
+		retention: retention,
+		interval:  interval,
+		now:       time.Now,
+	}
+}
+
+// Run purges right away and then once every interval, until the context is
+// cancelled. Failed purges are logged and retried on the next tick.
+func (j *Job) Run(ctx context.Context) error {
+	if j.retention <= 0 || j.interval <= 0 {
+		return errors.New("retention and interval must be positive")
+	}
+	log := logger.FromContext(ctx).With("job", j.name)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		j.purge(ctx)
+		select {
+		case <-ctx.Done():
+			log.Info("retention job stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Job) purge(ctx context.Context) {
+	log := logger.FromContext(ctx).With("job", j.name)
+	before := j.now().Add(-j.retention)
+	n, err := j.purger.Purge(ctx, before)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error("failed to purge", "before", before, "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		log.Info("purged", "count", n, "before", before)
+	}
+}
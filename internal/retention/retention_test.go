@@ -0,0 +1,66 @@
+package retention_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/retention"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePurger struct {
+	calls  []time.Time
+	err    error
+	stopAt int
+	cancel context.CancelFunc
+}
+
+func (f *fakePurger) Purge(_ context.Context, before time.Time) (int64, error) {
+	f.calls = append(f.calls, before)
+	if len(f.calls) >= f.stopAt {
+		f.cancel()
+	}
+	return 1, f.err
+}
+
+func TestJob_Run(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{name: "purges every interval"},
+		{name: "keeps running on error", err: errors.New("db error")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			p := &fakePurger{err: tc.err, stopAt: 3, cancel: cancel}
+			retentionPeriod := 24 * time.Hour
+			start := time.Now()
+
+			// Act
+			err := retention.NewJob("test", p, retentionPeriod, time.Millisecond).Run(ctx)
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, p.calls, 3)
+			for _, before := range p.calls {
+				assert.WithinDuration(t, start.Add(-retentionPeriod), before, time.Second)
+			}
+		})
+	}
+}
+
+func TestJob_Run_InvalidConfig(t *testing.T) {
+	// Act
+	err := retention.NewJob("test", &fakePurger{}, 0, time.Hour).Run(context.Background())
+
+	// Assert
+	require.Error(t, err)
+}
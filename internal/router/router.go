@@ -3,23 +3,123 @@ package router
 import (
 	"net/http"
 
+	"github.com/prashsamosa/newsapi/internal/auth"
 	"github.com/prashsamosa/newsapi/internal/handler"
+	"github.com/prashsamosa/newsapi/internal/health"
+	"github.com/prashsamosa/newsapi/internal/metrics"
+	"github.com/prashsamosa/newsapi/internal/news"
+	"github.com/prashsamosa/newsapi/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Storer represents all the store operations used by the routes.
+type Storer interface {
+	handler.NewsStorer
+	handler.TrashStorer
+	handler.AuditStorer
+	handler.RevisionStorer
+	handler.StatusStorer
+}
+
+// Options holds the optional dependencies of the routes.
+type Options struct {
+	// Idempotency stores the Idempotency-Key of the create requests. The
+	// header is ignored when nil.
+	Idempotency handler.IdempotencyStorer
+	// Authenticator validates the credentials of the requests, which must
+	// carry the scope required by the route. Authentication is disabled
+	// when nil.
+	Authenticator handler.Authenticator
+	// Metrics records the requests of every route and is served on
+	// /metrics. Metrics are disabled when nil.
+	Metrics *metrics.Metrics
+	// TracerProvider traces the requests of every route. Tracing is
+	// disabled when nil.
+	TracerProvider trace.TracerProvider
+	// Health serves the liveness, readiness and startup probes. The probes
+	// are disabled when nil.
+	Health *health.Health
+}
+
 // New creates a new router with all the handlers configured.
-func New(ns handler.NewsStorer) *http.ServeMux {
+func New(s Storer, opts Options) *http.ServeMux {
 	r := http.NewServeMux()
 
+	// handle registers the handler of the route, instrumented when the
+	// metrics or the tracing are enabled.
+	handle := func(pattern string, h http.Handler) {
+		if opts.Metrics != nil {
+			h = opts.Metrics.Instrument(pattern, h)
+		}
+		if opts.TracerProvider != nil {
+			h = tracing.Instrument(opts.TracerProvider, pattern, h)
+		}
+		r.Handle(pattern, h)
+	}
+
+	// scoped restricts the handler to the principals with the scope.
+	scoped := func(scope auth.Scope, h http.Handler) http.Handler {
+		if opts.Authenticator == nil {
+			return h
+		}
+		return handler.Authenticate(opts.Authenticator, handler.RequireScope(scope, h))
+	}
+
 	// Create news route.
-	r.HandleFunc("POST /news", handler.PostNews(ns))
+	var postNews http.Handler = handler.PostNews(s)
+	if opts.Idempotency != nil {
+		postNews = handler.Idempotent(opts.Idempotency, postNews)
+	}
+	handle("POST /news", scoped(auth.ScopeNewsWrite, postNews))
+	// Create, update and delete news in bulk.
+	handle("POST /news:batch", scoped(auth.ScopeNewsWrite, handler.BatchNews(s)))
 	// Get all news.
-	r.HandleFunc("GET /news", handler.GetAllNews(ns))
+	handle("GET /news", scoped(auth.ScopeNewsRead, handler.GetAllNews(s)))
+	// Full-text search over news.
+	handle("GET /news/search", scoped(auth.ScopeNewsRead, handler.SearchNews(s)))
+	// Get deleted news.
+	handle("GET /news/trash", scoped(auth.ScopeNewsWrite, handler.GetTrash(s)))
 	// Get news by ID.
-	r.HandleFunc("GET /news/{news_id}", handler.GetNewsByID(ns))
+	handle("GET /news/{news_id}", scoped(auth.ScopeNewsRead, handler.GetNewsByID(s)))
 	// Update news by ID.
-	r.HandleFunc("PUT /news/{news_id}", handler.UpdateNewsByID(ns))
-	// Delete news by ID.
-	r.HandleFunc("DELETE /news/{news_id}", handler.DeleteNewsByID(ns))
+	handle("PUT /news/{news_id}", scoped(auth.ScopeNewsWrite, handler.UpdateNewsByID(s)))
+	// Partially update news by ID.
+	handle("PATCH /news/{news_id}", scoped(auth.ScopeNewsWrite, handler.PatchNewsByID(s)))
+	// Delete news by ID, or permanently with ?purge=true, which is
+	// restricted to admins.
+	handle("DELETE /news/{news_id}", handler.WithPurge(
+		scoped(auth.ScopeNewsWrite, handler.DeleteNewsByID(s)),
+		scoped(auth.ScopeNewsAdmin, handler.PurgeNewsByID(s)),
+	))
+	// Restore deleted news by ID.
+	handle("POST /news/{news_id}/restore", scoped(auth.ScopeNewsWrite, handler.RestoreNewsByID(s)))
+	// Move news through the editorial workflow.
+	handle("POST /news/{news_id}/submit", scoped(auth.ScopeNewsWrite, handler.TransitionNews(s, news.StatusReview)))
+	handle("POST /news/{news_id}/publish", scoped(auth.ScopeNewsWrite, handler.TransitionNews(s, news.StatusPublished)))
+	handle("POST /news/{news_id}/archive", scoped(auth.ScopeNewsWrite, handler.TransitionNews(s, news.StatusArchived)))
+	handle("POST /news/{news_id}/draft", scoped(auth.ScopeNewsWrite, handler.TransitionNews(s, news.StatusDraft)))
+	// Get the revisions of a news. They hold unpublished content, so they
+	// are restricted to editors.
+	handle("GET /news/{news_id}/revisions", scoped(handler.EditorScope, handler.GetRevisions(s)))
+	// Get the changes between two revisions of a news.
+	handle("GET /news/{news_id}/revisions/diff", scoped(handler.EditorScope, handler.GetRevisionDiff(s)))
+	// Get a revision of a news.
+	handle("GET /news/{news_id}/revisions/{revision}", scoped(handler.EditorScope, handler.GetRevision(s)))
+	// Update a news with the content of one of its revisions.
+	handle("POST /news/{news_id}/revisions/{revision}/restore", scoped(auth.ScopeNewsWrite, handler.RestoreRevision(s)))
+	// Get the audit log of a news.
+	handle("GET /news/{news_id}/audit", scoped(auth.ScopeNewsAdmin, handler.GetNewsAudit(s)))
+	// Get the audit log of all news.
+	handle("GET /audit", scoped(auth.ScopeNewsAdmin, handler.GetAudit(s)))
+
+	if opts.Metrics != nil {
+		r.Handle("GET /metrics", opts.Metrics.Handler())
+	}
+	if opts.Health != nil {
+		r.Handle("GET /healthz", opts.Health.Live())
+		r.Handle("GET /readyz", opts.Health.Ready())
+		r.Handle("GET /startupz", opts.Health.Startup())
+	}
 
 	return r
 }
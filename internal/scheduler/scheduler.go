@@ -0,0 +1,81 @@
+// Package scheduler periodically moves the news through the editorial
+// workflow, publishing the scheduled news and archiving the expired ones.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/logger"
+)
+
+// Store represents the store operations of the scheduled transitions.
+type Store interface {
+	// PublishDue publishes the news scheduled before now.
+	PublishDue(ctx context.Context, now time.Time) (int64, error)
+	// ExpireDue archives the news expired before now.
+	ExpireDue(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Clock returns the current time, time.Now outside of the tests.
+type Clock func() time.Time
+
+// Scheduler periodically publishes and archives the news whose time has
+// come.
+type Scheduler struct {
+	store    Store
+	interval time.Duration
+	now      Clock
+}
+
+// New returns a scheduler checking the news once every interval, at the
+// time of the clock.
+func New(s Store, interval time.Duration, clock Clock) *Scheduler {
+	return &Scheduler{
+		store:    s,
+		interval: interval,
+		now:      clock,
+	}
+}
+
+// Run checks the news right away and then once every interval, until the
+// context is cancelled. Failed checks are logged and retried on the next
+// tick.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+	log := logger.FromContext(ctx).With("job", "scheduler")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			log.Info("scheduler stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	log := logger.FromContext(ctx).With("job", "scheduler")
+	now := s.now()
+	if n, err := s.store.PublishDue(ctx, now); err != nil {
+		if ctx.Err() == nil {
+			log.Error("failed to publish the scheduled news", "error", err)
+		}
+	} else if n > 0 {
+		log.Info("published scheduled news", "count", n)
+	}
+	if n, err := s.store.ExpireDue(ctx, now); err != nil {
+		if ctx.Err() == nil {
+			log.Error("failed to archive the expired news", "error", err)
+		}
+	} else if n > 0 {
+		log.Info("archived expired news", "count", n)
+	}
+}
@@ -0,0 +1,81 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	published []time.Time
+	expired   []time.Time
+	err       error
+	stopAt    int
+	cancel    context.CancelFunc
+}
+
+func (f *fakeStore) PublishDue(_ context.Context, now time.Time) (int64, error) {
+	f.published = append(f.published, now)
+	return 1, f.err
+}
+
+func (f *fakeStore) ExpireDue(_ context.Context, now time.Time) (int64, error) {
+	f.expired = append(f.expired, now)
+	if len(f.expired) >= f.stopAt {
+		f.cancel()
+	}
+	return 1, f.err
+}
+
+// fakeClock advances by a minute every time it is read.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.now = c.now.Add(time.Minute)
+	return c.now
+}
+
+func TestScheduler_Run(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{name: "checks every interval"},
+		{name: "keeps running on error", err: errors.New("db error")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			s := &fakeStore{err: tc.err, stopAt: 3, cancel: cancel}
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			clock := &fakeClock{now: start}
+
+			// Act
+			err := scheduler.New(s, time.Millisecond, clock.Now).Run(ctx)
+
+			// Assert
+			require.NoError(t, err)
+			expected := []time.Time{start.Add(time.Minute), start.Add(2 * time.Minute), start.Add(3 * time.Minute)}
+			assert.Equal(t, expected, s.published)
+			assert.Equal(t, expected, s.expired)
+		})
+	}
+}
+
+func TestScheduler_Run_InvalidInterval(t *testing.T) {
+	// Act
+	err := scheduler.New(&fakeStore{}, 0, time.Now).Run(context.Background())
+
+	// Assert
+	require.Error(t, err)
+}
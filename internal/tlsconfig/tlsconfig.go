@@ -0,0 +1,225 @@
+// Package tlsconfig builds the TLS configuration of the server, reloading
+// the certificate and the client CA bundle when their files change.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/logger"
+)
+
+// Client authentication policies, effective when a client CA bundle is
+// configured.
+const (
+	ClientAuthRequire  = "require"
+	ClientAuthOptional = "optional"
+)
+
+// versions are the supported minimum TLS versions.
+var versions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Config is the TLS configuration of the server.
+type Config struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the CA bundle verifying the client certificates,
+	// mTLS is disabled when empty.
+	ClientCAFile string
+	ClientAuth   tls.ClientAuthType
+	MinVersion   uint16
+	// CipherSuites are the cipher suites of TLS 1.2, the Go defaults when
+	// empty. TLS 1.3 suites are not configurable.
+	CipherSuites []uint16
+}
+
+// ParseVersion parses a minimum TLS version: 1.2 or 1.3.
+func ParseVersion(s string) (uint16, error) {
+	v, ok := versions[s]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS version %q, must be 1.2 or 1.3", s)
+	}
+	return v, nil
+}
+
+// ParseClientAuth parses a client authentication policy: require or
+// optional.
+func ParseClientAuth(s string) (tls.ClientAuthType, error) {
+	switch s {
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	case ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven, nil
+	default:
+		return 0, fmt.Errorf("unsupported client auth %q, must be %s or %s", s, ClientAuthRequire, ClientAuthOptional)
+	}
+}
+
+// ParseCipherSuites parses a comma separated list of cipher suite names,
+// like TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256. Insecure suites are
+// rejected.
+func ParseCipherSuites(s string) ([]uint16, error) {
+	if s == "" {
+		return nil, nil
+	}
+	ids := make(map[string]uint16)
+	for _, cs := range tls.CipherSuites() {
+		ids[cs.Name] = cs.ID
+	}
+
+	var (
+		suites []uint16
+		errs   []error
+	)
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		id, ok := ids[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unsupported cipher suite %q", name))
+			continue
+		}
+		suites = append(suites, id)
+	}
+	return suites, errors.Join(errs...)
+}
+
+// Reloader serves the certificate and the client CA bundle of the
+// configuration, reloaded when their files change.
+type Reloader struct {
+	cfg     Config
+	current atomic.Pointer[tls.Config]
+	// modTimes are the modification times of the loaded files.
+	modTimes []time.Time
+}
+
+// New returns the reloader with the files of the configuration loaded.
+func New(cfg Config) (*Reloader, error) {
+	r := &Reloader{cfg: cfg}
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.modTimes = modTimes
+	return r, nil
+}
+
+// TLSConfig returns the configuration of the server. Every handshake uses
+// the last loaded certificate and client CA bundle. It offers HTTP/2.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.cfg.MinVersion,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.current.Load().Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Run checks the files once every interval until the context is cancelled,
+// and reloads them when they changed. Failed reloads are logged and the
+// previous certificate is kept.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+	log := logger.FromContext(ctx).With("job", "tls_reload")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("tls reload stopped")
+			return nil
+		case <-ticker.C:
+		}
+		reloaded, err := r.Reload()
+		if err != nil {
+			log.Error("failed to reload the certificates", "error", err)
+			continue
+		}
+		if reloaded {
+			log.Info("certificates reloaded")
+		}
+	}
+}
+
+// Reload loads the files again when one of them changed since the last
+// load. It reports whether they were reloaded. It must not be called
+// concurrently with Run.
+func (r *Reloader) Reload() (bool, error) {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return false, err
+	}
+	if slices.EqualFunc(modTimes, r.modTimes, time.Time.Equal) {
+		return false, nil
+	}
+	if err := r.load(); err != nil {
+		return false, err
+	}
+	r.modTimes = modTimes
+	return true, nil
+}
+
+// load loads the certificate and the client CA bundle.
+func (r *Reloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   r.cfg.MinVersion,
+		CipherSuites: r.cfg.CipherSuites,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate in client CA bundle %s", r.cfg.ClientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = r.cfg.ClientAuth
+	}
+	r.current.Store(cfg)
+	return nil
+}
+
+// statFiles returns the modification times of the files.
+func (r *Reloader) statFiles() ([]time.Time, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	modTimes := make([]time.Time, 0, len(files))
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("stat: %w", err)
+		}
+		modTimes = append(modTimes, fi.ModTime())
+	}
+	return modTimes, nil
+}
@@ -0,0 +1,271 @@
+package tlsconfig_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prashsamosa/newsapi/internal/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authority signs the certificates of the tests.
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(tb testing.TB) *authority {
+	tb.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(tb, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(tb, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(tb, err)
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM encoded certificate and key of the common name.
+func (a *authority) issue(tb testing.TB, cn string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	tb.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(tb, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(tb, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.cert, &key.PublicKey, a.key)
+	require.NoError(tb, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(tb, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile writes the file and moves its modification time forward, so
+// that successive writes are seen as changes.
+func writeFile(tb testing.TB, path string, content []byte, modTime time.Time) {
+	tb.Helper()
+	require.NoError(tb, os.WriteFile(path, content, 0o600))
+	require.NoError(tb, os.Chtimes(path, modTime, modTime))
+}
+
+// serve serves HTTPS with the reloader and returns the address.
+func serve(tb testing.TB, r *tlsconfig.Reloader) string {
+	tb.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(tb, err)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+		TLSConfig:         r.TLSConfig(),
+		ReadHeaderTimeout: time.Second,
+	}
+	go func() {
+		if err := srv.ServeTLS(ln, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			tb.Error(err)
+		}
+	}()
+	tb.Cleanup(func() { srv.Close() })
+	return "https://" + ln.Addr().String()
+}
+
+// get requests the URL trusting the authority, with the client
+// certificates.
+func get(url string, ca *authority, certs ...tls.Certificate) (*http.Response, error) {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: certs, MinVersion: tls.VersionTLS12},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	testCases := []struct {
+		name          string
+		suites        string
+		expected      []uint16
+		expectedError bool
+	}{
+		{name: "empty", suites: ""},
+		{
+			name:     "secure suites",
+			suites:   "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+			expected: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256},
+		},
+		{name: "insecure suite", suites: "TLS_RSA_WITH_RC4_128_SHA", expectedError: true},
+		{name: "unknown suite", suites: "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,AES", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			suites, err := tlsconfig.ParseCipherSuites(tc.suites)
+
+			// Assert
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, suites)
+		})
+	}
+}
+
+func TestReloader_HTTP2(t *testing.T) {
+	// Arrange
+	ca := newAuthority(t)
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, filepath.Join(dir, "tls.crt"), certPEM, time.Now())
+	writeFile(t, filepath.Join(dir, "tls.key"), keyPEM, time.Now())
+	r, err := tlsconfig.New(tlsconfig.Config{
+		CertFile:   filepath.Join(dir, "tls.crt"),
+		KeyFile:    filepath.Join(dir, "tls.key"),
+		MinVersion: tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	url := serve(t, r)
+
+	// Act
+	resp, err := get(url, ca)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "HTTP/2.0", resp.Proto)
+}
+
+func TestReloader_ClientAuth(t *testing.T) {
+	ca := newAuthority(t)
+	clientCA := newAuthority(t)
+	certPEM, keyPEM := clientCA.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	otherPEM, otherKeyPEM := newAuthority(t).issue(t, "other", x509.ExtKeyUsageClientAuth)
+	otherCert, err := tls.X509KeyPair(otherPEM, otherKeyPEM)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		clientAuth    tls.ClientAuthType
+		certs         []tls.Certificate
+		expectedError bool
+	}{
+		{name: "required and given", clientAuth: tls.RequireAndVerifyClientCert, certs: []tls.Certificate{clientCert}},
+		{name: "required and missing", clientAuth: tls.RequireAndVerifyClientCert, expectedError: true},
+		{name: "required and untrusted", clientAuth: tls.RequireAndVerifyClientCert, certs: []tls.Certificate{otherCert}, expectedError: true},
+		{name: "optional and missing", clientAuth: tls.VerifyClientCertIfGiven},
+		{name: "optional and untrusted", clientAuth: tls.VerifyClientCertIfGiven, certs: []tls.Certificate{otherCert}, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			dir := t.TempDir()
+			serverPEM, serverKeyPEM := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+			writeFile(t, filepath.Join(dir, "tls.crt"), serverPEM, time.Now())
+			writeFile(t, filepath.Join(dir, "tls.key"), serverKeyPEM, time.Now())
+			writeFile(t, filepath.Join(dir, "ca.crt"), clientCA.pem, time.Now())
+			r, err := tlsconfig.New(tlsconfig.Config{
+				CertFile:     filepath.Join(dir, "tls.crt"),
+				KeyFile:      filepath.Join(dir, "tls.key"),
+				ClientCAFile: filepath.Join(dir, "ca.crt"),
+				ClientAuth:   tc.clientAuth,
+				MinVersion:   tls.VersionTLS12,
+			})
+			require.NoError(t, err)
+			url := serve(t, r)
+
+			// Act
+			resp, err := get(url, ca, tc.certs...)
+
+			// Assert
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		})
+	}
+}
+
+func TestReloader_Reload(t *testing.T) {
+	// Arrange
+	ca := newAuthority(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	modTime := time.Now().Add(-time.Minute)
+	certPEM, keyPEM := ca.issue(t, "first", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, modTime)
+	writeFile(t, keyFile, keyPEM, modTime)
+	r, err := tlsconfig.New(tlsconfig.Config{CertFile: certFile, KeyFile: keyFile, MinVersion: tls.VersionTLS12})
+	require.NoError(t, err)
+	url := serve(t, r)
+
+	// Act & Assert: unchanged files are not reloaded.
+	reloaded, err := r.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// A new certificate is served once reloaded.
+	certPEM, keyPEM = ca.issue(t, "second", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, modTime.Add(time.Second))
+	writeFile(t, keyFile, keyPEM, modTime.Add(time.Second))
+	reloaded, err = r.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	resp, err := get(url, ca)
+	require.NoError(t, err)
+	assert.Equal(t, "second", resp.TLS.PeerCertificates[0].Subject.CommonName)
+
+	// An invalid certificate is not loaded and the previous one is kept.
+	writeFile(t, certFile, []byte("not a certificate"), modTime.Add(2*time.Second))
+	reloaded, err = r.Reload()
+	require.Error(t, err)
+	assert.False(t, reloaded)
+	resp, err = get(url, ca)
+	require.NoError(t, err)
+	assert.Equal(t, "second", resp.TLS.PeerCertificates[0].Subject.CommonName)
+}
@@ -0,0 +1,98 @@
+// Package tracing traces the HTTP requests and the database queries with
+// OpenTelemetry.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prashsamosa/newsapi/internal/logger"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/extra/bunotel"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the name of the service in the traces.
+const ServiceName = "newsapi"
+
+// Span exporters.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	// ExporterMemory keeps the spans in memory, for the tests.
+	ExporterMemory = "memory"
+)
+
+// Propagator propagates the W3C trace context and baggage.
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// NewExporter returns the span exporter of the name, nil for none. The OTLP
+// exporter sends the spans over HTTP and is configured with the standard
+// OTEL_EXPORTER_OTLP_* environment variables.
+func NewExporter(ctx context.Context, name string) (sdktrace.SpanExporter, error) {
+	switch name {
+	case "", ExporterNone:
+		return nil, nil //nolint:nilnil // no exporter means tracing is disabled.
+	case ExporterOTLP:
+		return otlptracehttp.New(ctx)
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterMemory:
+		return tracetest.NewInMemoryExporter(), nil
+	default:
+		return nil, fmt.Errorf("unknown span exporter: %s", name)
+	}
+}
+
+// NewProvider returns a tracer provider sending the spans to the exporter,
+// in batches except for the in-memory exporter which gets them right away.
+func NewProvider(exp sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(ServiceName)))
+	if err != nil {
+		res = resource.Default()
+	}
+	export := sdktrace.WithBatcher(exp)
+	if _, ok := exp.(*tracetest.InMemoryExporter); ok {
+		export = sdktrace.WithSyncer(exp)
+	}
+	return sdktrace.NewTracerProvider(export, sdktrace.WithResource(res))
+}
+
+// Instrument opens a span named after the route for every request served
+// by the handler, continuing the trace of the traceparent header. The trace
+// and span IDs are added to the logger of the request context.
+func Instrument(tp trace.TracerProvider, route string, next http.Handler) http.Handler {
+	return otelhttp.NewHandler(withTraceLogger(next), route,
+		otelhttp.WithTracerProvider(tp),
+		otelhttp.WithPropagators(Propagator),
+	)
+}
+
+// withTraceLogger adds the IDs of the span of the request to its logger.
+func withTraceLogger(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sc := trace.SpanContextFromContext(ctx)
+		if sc.IsValid() {
+			log := logger.FromContext(ctx).With("traceId", sc.TraceID().String(), "spanId", sc.SpanID().String())
+			r = r.WithContext(logger.CtxWithLogger(ctx, log))
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// InstrumentDB opens a span for every query run on the database, as a
+// child of the span of the query context.
+func InstrumentDB(tp trace.TracerProvider, db *bun.DB) {
+	db.AddQueryHook(bunotel.NewQueryHook(bunotel.WithTracerProvider(tp)))
+}
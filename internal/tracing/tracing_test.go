@@ -0,0 +1,91 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prashsamosa/newsapi/internal/logger"
+	"github.com/prashsamosa/newsapi/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewExporter(t *testing.T) {
+	testCases := []struct {
+		name        string
+		exporter    string
+		expectedNil bool
+		expectedErr bool
+	}{
+		{name: "default", expectedNil: true},
+		{name: "none", exporter: tracing.ExporterNone, expectedNil: true},
+		{name: "stdout", exporter: tracing.ExporterStdout},
+		{name: "memory", exporter: tracing.ExporterMemory},
+		{name: "unknown", exporter: "zipkin", expectedNil: true, expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			exp, err := tracing.NewExporter(context.Background(), tc.exporter)
+
+			// Assert
+			assert.Equal(t, tc.expectedErr, err != nil)
+			assert.Equal(t, tc.expectedNil, exp == nil)
+		})
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+	testCases := []struct {
+		name        string
+		traceparent string
+	}{
+		{name: "new trace"},
+		{name: "propagated trace", traceparent: "00-" + traceID + "-" + parentSpanID + "-01"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			exp, err := tracing.NewExporter(context.Background(), tracing.ExporterMemory)
+			require.NoError(t, err)
+			tp := tracing.NewProvider(exp)
+			var logs bytes.Buffer
+			next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				logger.FromContext(r.Context()).Info("handled")
+			})
+			r := httptest.NewRequest(http.MethodGet, "/news", http.NoBody)
+			r = r.WithContext(logger.CtxWithLogger(r.Context(), slog.New(slog.NewJSONHandler(&logs, nil))))
+			if tc.traceparent != "" {
+				r.Header.Set("traceparent", tc.traceparent)
+			}
+
+			// Act
+			tracing.Instrument(tp, "GET /news", next).ServeHTTP(httptest.NewRecorder(), r)
+
+			// Assert
+			spans := exp.(*tracetest.InMemoryExporter).GetSpans()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, "GET /news", span.Name)
+			assert.Contains(t, logs.String(), `"traceId":"`+span.SpanContext.TraceID().String()+`"`)
+			assert.Contains(t, logs.String(), `"spanId":"`+span.SpanContext.SpanID().String()+`"`)
+			if tc.traceparent != "" {
+				assert.Equal(t, traceID, span.SpanContext.TraceID().String())
+				assert.Equal(t, parentSpanID, span.Parent.SpanID().String())
+			} else {
+				assert.False(t, span.Parent.IsValid())
+			}
+		})
+	}
+}